@@ -0,0 +1,174 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	// canonicalNames maps the normalized form of known authors' names
+	// variants to their canonical spelling.
+	canonicalNames = make(map[string]string)
+
+	// realNames maps the normalized form of known pseudonyms to the canonical
+	// name of the person behind them.
+	realNames = make(map[string]string)
+)
+
+// Authority is a user-maintained entry of the authors' authority file. It
+// defines the canonical spelling of an author's name and the variants it is
+// known under.
+type Authority struct {
+	// Name is the canonical spelling of the author's name.
+	Name string `json:"name"`
+
+	// Aliases lists other spellings of the author's name (like "H. G. Wells"
+	// or "Wells, Herbert George" for "H.G. Wells"). Aliases are replaced by
+	// the canonical Name.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Pseudonyms lists the pen names used by the author. Unlike aliases,
+	// pseudonyms are kept as-is but are considered as the same person when
+	// comparing Books.
+	Pseudonyms []string `json:"pseudonyms,omitempty"`
+}
+
+// LoadAuthority reads a list of Authority from a JSON file and adds them to
+// the authors' names known by Book.
+func LoadAuthority(path string) error {
+	//#nosec G304 -- path is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var authorities []Authority
+	if err := json.NewDecoder(f).Decode(&authorities); err != nil {
+		return fmt.Errorf("fail to decode authority file %s: %v", path, err)
+	}
+
+	return AddAuthority(authorities...)
+}
+
+// AddAuthority adds Authority entries to the authors' names known by Book.
+// AddAuthority fails without adding any entry if a name is declared as the
+// variant of two different authors.
+func AddAuthority(authorities ...Authority) error {
+	canonicals, reals := make(map[string]string), make(map[string]string)
+
+	declare := func(names map[string]string, variant, canonical string) error {
+		key := nameKey(variant)
+		if key == "" {
+			return nil
+		}
+
+		for _, known := range []map[string]string{canonicalNames, realNames, canonicals, reals} {
+			if c, exists := known[key]; exists && c != canonical {
+				return fmt.Errorf("'%s' is already declared as a variant of '%s'", variant, c)
+			}
+		}
+
+		names[key] = canonical
+		return nil
+	}
+
+	for i, a := range authorities {
+		name := cleanAuthorName(strings.TrimSpace(a.Name))
+		if name == "" {
+			return fmt.Errorf("invalid authority #%d: no name", i+1)
+		}
+
+		for _, alias := range append([]string{name}, a.Aliases...) {
+			if err := declare(canonicals, alias, name); err != nil {
+				return fmt.Errorf("invalid authority #%d: %v", i+1, err)
+			}
+		}
+
+		for _, pseudonym := range a.Pseudonyms {
+			if err := declare(reals, pseudonym, name); err != nil {
+				return fmt.Errorf("invalid authority #%d: %v", i+1, err)
+			}
+		}
+	}
+
+	for key, name := range canonicals {
+		canonicalNames[key] = name
+	}
+
+	for key, name := range reals {
+		realNames[key] = name
+	}
+
+	return nil
+}
+
+// CanonicalName returns the canonical spelling of an author's name as
+// defined in the authority file. Names that are not known are returned
+// as-is.
+func CanonicalName(name string) string {
+	if canonical, known := canonicalNames[nameKey(name)]; known {
+		return canonical
+	}
+	return name
+}
+
+// SuggestAuthority groups names that are likely variants of the same
+// author's name, as found by comparing their normalized forms. It returns an
+// Authority for each group of at least two names, whose canonical Name is the
+// first of the group's names in the supplied order (usually the most
+// frequent one).
+// Names that are already known as variants of the same author are not
+// reported.
+func SuggestAuthority(names []string) []Authority {
+	suggestions := []Authority{}
+	for _, group := range groupVariants(names, func(name1, name2 string) bool {
+		return !isDeclaredVariant(name1, name2) && areNameVariants(name1, name2)
+	}) {
+		suggestions = append(suggestions, Authority{Name: group[0], Aliases: group[1:]})
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return NormalizeString(SortName(suggestions[i].Name)) < NormalizeString(SortName(suggestions[j].Name))
+	})
+
+	return suggestions
+}
+
+// areNameVariants checks whether two names are likely to be spelling
+// variants of the same name.
+func areNameVariants(name1, name2 string) bool {
+	if compareStrings(nameKey(name1), nameKey(name2)) >= AreAlmostTheSame {
+		return true
+	}
+	return compareNormalizedStrings(SortName(name1), SortName(name2)) >= AreAlmostTheSame
+}
+
+// isDeclaredVariant checks whether two names are different names of the same
+// person according to the authority file.
+func isDeclaredVariant(name1, name2 string) bool {
+	return nameKey(name1) != nameKey(name2) && nameIdentity(name1) == nameIdentity(name2)
+}
+
+// nameIdentity returns a normalized identifier of the person behind a name,
+// resolving aliases and pseudonyms declared in the authority file. Names of
+// the same person share the same identity.
+func nameIdentity(name string) string {
+	key := nameKey(name)
+	if real, known := realNames[key]; known {
+		return nameKey(real)
+	}
+	if canonical, known := canonicalNames[key]; known {
+		return nameKey(canonical)
+	}
+	return key
+}
+
+// nameKey returns the normalized form of a name used to look-up the
+// authority file.
+func nameKey(name string) string {
+	return strings.Join(strings.Fields(NormalizeString(cleanAuthorName(name))), " ")
+}
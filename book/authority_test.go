@@ -0,0 +1,102 @@
+package book
+
+import (
+	"fmt"
+	"testing"
+)
+
+func resetAuthority() {
+	canonicalNames, realNames = make(map[string]string), make(map[string]string)
+}
+
+func TestAddAuthority(t *testing.T) {
+	testCases := []struct {
+		in  []Authority
+		err bool
+	}{
+		{[]Authority{{Name: "H. G. Wells", Aliases: []string{"H.G. Wells"}}}, false},
+		{[]Authority{{Name: "Romain Gary", Pseudonyms: []string{"Émile Ajar"}}}, false},
+		{[]Authority{{Aliases: []string{"H.G. Wells"}}}, true},
+		{[]Authority{{Name: "Romain Gary", Pseudonyms: []string{"Émile Ajar"}}, {Name: "Paul Pavlowitch", Aliases: []string{"Emile Ajar"}}}, true},
+	}
+
+	defer resetAuthority()
+	for _, tc := range testCases {
+		resetAuthority()
+		if err := AddAuthority(tc.in...); (err != nil) != tc.err {
+			t.Errorf("Adding authority %#v failed:\nWant error: %v\nGot : %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestCanonicalName(t *testing.T) {
+	defer resetAuthority()
+	if err := LoadAuthority("testdata/authority/authority.json"); err != nil {
+		t.Fatalf("fail to load authority file: %v", err)
+	}
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"H.G. Wells", "H. G. Wells"},
+		{"Wells, Herbert George", "H. G. Wells"},
+		{"WELLS, H. G.", "H. G. Wells"},
+		{"h. g. wells", "H. G. Wells"},
+		{"Romain Kacew", "Romain Gary"},
+		{"Émile Ajar", "Émile Ajar"},
+		{"Jules Verne", "Jules Verne"},
+	}
+
+	for _, tc := range testCases {
+		if got := CanonicalName(tc.in); got != tc.want {
+			t.Errorf("Canonical name of %#v failed.\nWant: %#v\nGot : %#v", tc.in, tc.want, got)
+		}
+	}
+
+	t.Run("SetAuthors", func(t *testing.T) {
+		b := &Book{Report: NewReport()}
+		b.SetAuthors([]string{"Wells, Herbert George", "Émile Ajar"})
+
+		want := []string{"H. G. Wells", "Émile Ajar"}
+		if fmt.Sprint(b.Authors) != fmt.Sprint(want) || len(b.FileAs) != 0 {
+			t.Errorf("Setting Authors failed.\nWant: %#v\nGot : %#v (FileAs: %#v)", want, b.Authors, b.FileAs)
+		}
+	})
+
+	t.Run("AddContributor", func(t *testing.T) {
+		b := &Book{Report: NewReport()}
+		b.addContributor(RoleAuthor, "H.G. Wells", "Wells, H.G.")
+		b.addContributor(RoleAuthor, "Jules Verne", "Verne, Jules")
+
+		want := map[string]string{"Jules Verne": "Verne, Jules"}
+		if fmt.Sprint(b.FileAs) != fmt.Sprint(want) {
+			t.Errorf("Adding contributors failed.\nWant: %#v\nGot : %#v", want, b.FileAs)
+		}
+	})
+
+	t.Run("CompareAuthors", func(t *testing.T) {
+		b := &Book{Authors: []string{"Romain Gary"}}
+		b1 := &Book{Authors: []string{"Emile Ajar"}}
+		if got := b.compareAuthorsWith(b1); got != AreTheSame {
+			t.Errorf("Comparing pseudonyms failed.\nWant: %v\nGot : %v", AreTheSame, got)
+		}
+	})
+}
+
+func TestSuggestAuthority(t *testing.T) {
+	defer resetAuthority()
+	if err := AddAuthority(Authority{Name: "Romain Gary", Pseudonyms: []string{"Émile Ajar"}}); err != nil {
+		t.Fatalf("fail to add authority: %v", err)
+	}
+
+	in := []string{"Jules Verne", "H.G. Wells", "Romain Gary", "H. G. Wells", "Jules Vernes", "Emile Ajar", "Pierre Pelot", "Herbert George Wells"}
+	want := []Authority{
+		{Name: "Jules Verne", Aliases: []string{"Jules Vernes"}},
+		{Name: "H.G. Wells", Aliases: []string{"H. G. Wells", "Herbert George Wells"}},
+	}
+
+	if got := SuggestAuthority(in); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Suggesting authors' merges failed.\nWant: %#v\nGot : %#v", want, got)
+	}
+}
@@ -34,6 +34,11 @@ var (
 	// forename.
 	reAuthName = regexp.MustCompile(`\s?,\s?`)
 
+	// MarkdownDescription keeps the formatting of Book's Description
+	// (paragraphs, lists, emphasis, links...) by converting it from HTML to
+	// Markdown instead of plain text.
+	MarkdownDescription = false
+
 	// reUpperCase is a regexp that identifies words in upper case.
 	reUpperCase = regexp.MustCompile(`\p{Lu}{3,}`)
 )
@@ -46,9 +51,25 @@ type Book struct {
 	// Title is the book's title.
 	Title string
 
-	// Authors is the list names of the authors and/or editors for this book.
+	// Authors is the list of names of the authors of this book.
 	Authors []string
 
+	// Translators is the list of names of the translators of this book.
+	Translators []string `json:",omitempty"`
+
+	// Contributors lists the names of the people or organizations that
+	// contributed to this book besides its Authors and Translators (like
+	// illustrators or editors). Contributors are grouped by role, roles are
+	// expressed as MARC relator codes (like 'ill' or 'edt').
+	Contributors map[string][]string `json:",omitempty"`
+
+	// FileAs maps the names of Book's Authors or contributors to their
+	// sortable form (like "Verne, Jules" for "Jules Verne"). It is populated
+	// from EPUB's 'file-as' or 'calibre:author_sort' metadata or from names
+	// provided in their sortable form. Book.SortName computes sortable names
+	// that are not known.
+	FileAs map[string]string `json:",omitempty"`
+
 	// ISBN is the unique industry standard identifier for this book.
 	// libro tends to prefer ISBN_13 format when available or when it can be
 	// derived from an ISBN_10.  ISBN10 and ISBN13 methods can be invoked to
@@ -64,12 +85,26 @@ type Book struct {
 	// heuristic.
 	AlternateISBN []string `json:",omitempty"`
 
+	// ISBNFormat maps Book's ISBN and AlternateISBN to the format of the
+	// edition they are attributed to ("ebook", "print" or "audio"), when
+	// known. libro prefers the ISBN of the ebook edition as Book's ISBN.
+	ISBNFormat map[string]string `json:",omitempty"`
+
+	// Identifiers maps schemes (like "asin", "doi" or "uuid") to the
+	// corresponding identifier of this book besides its ISBN. Identifiers
+	// are normalized using Book.SetIdentifier.
+	Identifiers map[string]string `json:",omitempty"`
+
 	// SubTitle is the book's sub-title.
 	SubTitle string `json:",omitempty"`
 
 	// Publisher is the publisher of this book.
 	Publisher string `json:",omitempty"`
 
+	// Edition is the edition statement of this book, like "2nd edition" or
+	// "Nouvelle édition revue et augmentée".
+	Edition string `json:",omitempty"`
+
 	// PublishedDate is the date of publication of this book.
 	// `libro` tries to normalize dates using '2006-01-02' format. When
 	// 'precision' of date is not enough to capture known month or days, date
@@ -78,8 +113,14 @@ type Book struct {
 	// 'normalized' using Book.SetPublishedDate.
 	PublishedDate string `json:",omitempty"`
 
+	// OriginalPublishedDate is the date of the first publication of the
+	// book's work when this book is a later edition or a translation. It
+	// follows the same format than PublishedDate.
+	OriginalPublishedDate string `json:",omitempty"`
+
 	// Description is the synopsis of the book. The text of the description
-	// is formatted in HTML and includes simple formatting elements.
+	// is cleaned from HTML formatting directives and is either plain text or
+	// Markdown (see MarkdownDescription).
 	Description string `json:",omitempty"`
 
 	// Series is the series to which this book belongs to.
@@ -92,11 +133,25 @@ type Book struct {
 	// SubTitle information).
 	SeriesTitle string `json:",omitempty"`
 
-	// Language is the book's language. It is the two-letter ISO 639-1 code
-	// such as 'fr', 'en'.
+	// OriginalTitle is the title of the original work when this book is a
+	// translation.
+	OriginalTitle string `json:",omitempty"`
+
+	// OriginalLanguage is the language of the original work when this book
+	// is a translation. It follows the same format than Language.
+	OriginalLanguage string `json:",omitempty"`
+
+	// Language is the book's language. It is a BCP 47 language tag such as
+	// 'fr', 'en' or 'pt-BR'. The two-letter ISO 639-1 code is used for the
+	// language itself when it exists, script or region are only kept if
+	// known.
+	// Most Book's functions dealing with Language will better work if
+	// Language is 'normalized' using Book.SetLanguage.
 	Language string `json:",omitempty"`
 
-	// PageCount is total number of pages of this book.
+	// PageCount is total number of pages of this book. If unknown, it can be
+	// estimated from the number of words of the book using
+	// Book.EstimatePageCount.
 	PageCount int64 `json:",omitempty"`
 
 	// Subject is the list of subject categories, such as "Fiction",
@@ -156,23 +211,82 @@ func (b *Book) SetPublishedDate(date string) {
 	b.PublishedDate = NormalizeDate(date)
 }
 
+// SetOriginalPublishedDate sets Book's OriginalPublishedDate and tries to
+// normalize its format. Unlike PublishedDate, original works can be ancient.
+func (b *Book) SetOriginalPublishedDate(date string) {
+	t, err := ParseTimestamp(date)
+	if err != nil {
+		b.ReportIssue("unrecognized OriginalPublishedDate (%s)", date)
+		return
+	}
+
+	if t.Year() > time.Now().Year() {
+		b.ReportWarning("suspicious OriginalPublishedDate (%s)", date)
+		return
+	}
+
+	b.OriginalPublishedDate = NormalizeDate(date)
+}
+
 // SetAuthors sets Book's Authors and tries to keep Authors' names and
 // surnames in a pre-defined order.
 func (b *Book) SetAuthors(authors []string) {
 	b.Authors = make([]string, len(authors))
 
 	for i, author := range authors {
-		b.Authors[i] = cleanAuthorName(author)
+		b.Authors[i] = b.canonicalName(b.cleanName(author))
+	}
+}
+
+// SetTranslators sets Book's Translators and tries to keep Translators' names
+// and surnames in a pre-defined order.
+func (b *Book) SetTranslators(translators []string) {
+	b.Translators = make([]string, len(translators))
+
+	for i, translator := range translators {
+		b.Translators[i] = b.cleanName(translator)
+	}
+}
+
+// SetFileAs records the sortable form of one of Book's contributors' name.
+func (b *Book) SetFileAs(name string, fileAs string) {
+	if name == "" || fileAs == "" || fileAs == name {
+		return
+	}
+
+	if b.FileAs == nil {
+		b.FileAs = make(map[string]string)
+	}
+	b.FileAs[name] = fileAs
+}
+
+// cleanName cleans a contributor's name using cleanAuthorName. If name was
+// given in its sortable form ("Surname, Forename"), the sortable form is
+// recorded in Book's FileAs.
+func (b *Book) cleanName(name string) string {
+	cleaned := cleanAuthorName(name)
+	if parts := reAuthName.Split(fixNameCase(name), 2); len(parts) == 2 {
+		b.SetFileAs(cleaned, strings.TrimSpace(parts[0])+", "+strings.TrimSpace(parts[1]))
+	}
+	return cleaned
+}
+
+// canonicalName returns the canonical spelling of an author's name as defined
+// in the authority file. The sortable form recorded for a replaced alias is
+// forgotten as it does not necessarily fit the canonical name.
+func (b *Book) canonicalName(name string) string {
+	canonical := CanonicalName(name)
+	if canonical != name {
+		Debug.Printf("use canonical name '%s' for author '%s'", canonical, name)
+		delete(b.FileAs, name)
 	}
+	return canonical
 }
 
 // cleanAuthorName tries to clean an Author name by reordering it or correcting
 // fancy case.
 func cleanAuthorName(author string) string {
-	a := reUpperCase.ReplaceAllStringFunc(author, func(w string) string {
-		r, sz := utf8.DecodeRuneInString(w)
-		return string(r) + strings.ToLower(w[sz:])
-	})
+	a := fixNameCase(author)
 
 	if name := reAuthName.Split(a, 2); len(name) == 2 {
 		return strings.TrimSpace(name[1] + " " + name[0])
@@ -181,37 +295,64 @@ func cleanAuthorName(author string) string {
 	return a
 }
 
+// fixNameCase corrects names written in upper-case.
+func fixNameCase(name string) string {
+	return reUpperCase.ReplaceAllStringFunc(name, func(w string) string {
+		r, sz := utf8.DecodeRuneInString(w)
+		return string(r) + strings.ToLower(w[sz:])
+	})
+}
+
 // SetDescription sets Book's Description and tries to clean it from un-helping
-// HTML formatting directives.
+// HTML formatting directives. If MarkdownDescription is set, Description's
+// formatting is kept as Markdown.
 func (b *Book) SetDescription(desc string) {
-	// TODO: use a better 'html to text' converter that preserves formatting
-	unhtml, err := htmlutil.GetRawTextFromHTML(strings.NewReader(desc))
+	html2txt := htmlutil.GetRawTextFromHTML
+	if MarkdownDescription {
+		html2txt = htmlutil.GetMarkdownFromHTML
+	}
+
+	unhtml, err := html2txt(strings.NewReader(desc))
 	if err != nil {
 		Debug.Printf("fail to clean Description from HTML tags: %v", err)
 		b.Description = desc
+		return
 	}
 
 	cleanDesc, err := io.ReadAll(unhtml)
 	if err != nil {
 		Debug.Printf("fail to clean Description from HTML tags: %v", err)
 		b.Description = desc
+		return
 	}
 
 	b.Description = string(cleanDesc)
 }
 
-// SetLanguage sets Book's Language and tries to filter exotic language names.
+// SetLanguage sets Book's Language and tries to normalize it to a BCP 47
+// language tag.
+// SetLanguage reports non-recognized Language but do not fail.
 func (b *Book) SetLanguage(lang string) {
-	b.Language = lang
-
-	if len(b.Language) > 2 {
-		b.Language = b.Language[:2]
+	normLang, err := NormalizeLanguage(lang)
+	if err != nil {
+		b.ReportWarning("unrecognized Language (%s): %v", lang, err)
+		return
 	}
 
-	// For some reason several of my ebook report "UN" as Language
-	if strings.EqualFold(b.Language, "un") {
-		b.Language = ""
+	b.Language = normLang
+}
+
+// SetOriginalLanguage sets Book's OriginalLanguage and tries to normalize it
+// to a BCP 47 language tag.
+// SetOriginalLanguage reports non-recognized OriginalLanguage but do not fail.
+func (b *Book) SetOriginalLanguage(lang string) {
+	normLang, err := NormalizeLanguage(lang)
+	if err != nil {
+		b.ReportWarning("unrecognized OriginalLanguage (%s): %v", lang, err)
+		return
 	}
+
+	b.OriginalLanguage = normLang
 }
 
 // PublishedYear returns the year of publication.
@@ -221,16 +362,46 @@ func (b *Book) PublishedYear() string {
 	return Year(b.PublishedDate)
 }
 
+// OriginalPublishedYear returns the year of the first publication of the
+// book's work, that is the year of OriginalPublishedDate or of PublishedDate
+// if unknown.
+func (b *Book) OriginalPublishedYear() string {
+	if b.OriginalPublishedDate != "" {
+		return Year(b.OriginalPublishedDate)
+	}
+	return b.PublishedYear()
+}
+
+// mapAttributes lists the names of the attributes supported by NewFromMap.
+var mapAttributes = []string{
+	"Title", "SubTitle", "SeriesTitle", "OriginalTitle", "Authors", "Translators",
+	"Publisher", "Edition", "PublishedDate", "OriginalPublishedDate",
+	"Description", "Series", "SeriesIndex", "ISBN", "ISBNFormat", "Language",
+	"OriginalLanguage", "PageCount", "Subject",
+}
+
 // NewFromMap creates a Book's from to the attributes defined as a map
 // where keys are attribute's name (insensitive to case) and value is a string
 // representation of the attribute's value.
 // For attributes that accept a list of values (like Authors or Subject),
 // provided map value should be formatted like "val0 & val1" (individual value
 // in as string separated by '&').
+// Identifiers are set using "Identifier.<scheme>" keys (like
+// "Identifier.asin"). ISBNFormat is the format of the edition ISBN is
+// attributed to.
 func NewFromMap(m map[string]string) (*Book, error) {
 	b := New()
 
+	var isbnFormat string
 	for attr, value := range m {
+		if scheme := strings.TrimPrefix(strings.ToLower(attr), "identifier."); scheme != strings.ToLower(attr) {
+			if _, known := identifierSchemes[scheme]; !known {
+				return nil, fmt.Errorf("cannot set unknown identifier '%s'", scheme)
+			}
+			b.SetIdentifier(scheme, value)
+			continue
+		}
+
 		switch a := strings.Title(attr); a {
 		case "Title":
 			b.Title = value
@@ -241,15 +412,27 @@ func NewFromMap(m map[string]string) (*Book, error) {
 		case "SeriesTitle":
 			b.SeriesTitle = value
 
+		case "OriginalTitle":
+			b.OriginalTitle = value
+
 		case "Authors":
 			b.SetAuthors(reList.Split(value, -1))
 
+		case "Translators":
+			b.SetTranslators(reList.Split(value, -1))
+
 		case "Publisher":
 			b.Publisher = value
 
+		case "Edition":
+			b.Edition = value
+
 		case "PublishedDate":
 			b.SetPublishedDate(value)
 
+		case "OriginalPublishedDate":
+			b.SetOriginalPublishedDate(value)
+
 		case "Description":
 			b.SetDescription(value)
 
@@ -265,9 +448,15 @@ func NewFromMap(m map[string]string) (*Book, error) {
 		case "ISBN":
 			b.SetISBN(value)
 
+		case "ISBNFormat":
+			isbnFormat = value
+
 		case "Language":
 			b.SetLanguage(value)
 
+		case "OriginalLanguage":
+			b.SetOriginalLanguage(value)
+
 		case "PageCount":
 			var err error
 			if b.PageCount, err = strconv.ParseInt(value, 10, 0); err != nil {
@@ -282,6 +471,13 @@ func NewFromMap(m map[string]string) (*Book, error) {
 		}
 	}
 
+	if isbnFormat != "" {
+		if b.ISBN == "" {
+			return nil, fmt.Errorf("cannot assign format %s to an unknown ISBN", isbnFormat)
+		}
+		b.SetISBNFormat(b.ISBN, isbnFormat)
+	}
+
 	return b, nil
 }
 
@@ -319,17 +515,98 @@ func (b *Book) MergeWith(b1 *Book, override bool) {
 		}
 	}
 
+	if len(b1.Translators) > 0 {
+		if len(b.Translators) == 0 {
+			Verbose.Printf("set empty Translators to %v", b1.Translators)
+			b.Translators = append([]string{}, b1.Translators...)
+		} else if override {
+			if compareLists(b.Translators, b1.Translators) < AreAlmostTheSame {
+				b.ReportWarning("changed Translators from %v to %v", b.Translators, b1.Translators)
+			} else {
+				Verbose.Printf("changed Translators from %v to %v", b.Translators, b1.Translators)
+			}
+
+			b.Translators = append([]string{}, b1.Translators...)
+		}
+	}
+
+	for name, fileAs := range b1.FileAs {
+		if b.FileAs[name] == "" {
+			b.SetFileAs(name, fileAs)
+		} else if override && b.FileAs[name] != fileAs {
+			Verbose.Printf("changed FileAs of %s from %v to %v", name, b.FileAs[name], fileAs)
+			b.FileAs[name] = fileAs
+		}
+	}
+
+	for role, names := range b1.Contributors {
+		if len(names) == 0 {
+			continue
+		}
+
+		if b.Contributors == nil {
+			b.Contributors = make(map[string][]string)
+		}
+
+		if len(b.Contributors[role]) == 0 {
+			Verbose.Printf("set empty Contributors (%s) to %v", role, names)
+			b.Contributors[role] = append([]string{}, names...)
+		} else if override && compareLists(b.Contributors[role], names) != AreTheSame {
+			Verbose.Printf("changed Contributors (%s) from %v to %v", role, b.Contributors[role], names)
+			b.Contributors[role] = append([]string{}, names...)
+		}
+	}
+
+	for isbn, format := range b1.ISBNFormat {
+		if b.ISBNFormat[isbn] == "" {
+			b.SetISBNFormat(isbn, format)
+		} else if override && b.ISBNFormat[isbn] != format {
+			Verbose.Printf("changed format of ISBN %s from %v to %v", isbn, b.ISBNFormat[isbn], format)
+			b.ISBNFormat[isbn] = format
+		}
+	}
+
 	if b1.ISBN != "" {
 		if b.ISBN == "" {
 			b.ReportWarning("set empty ISBN to %v", b1.ISBN)
 			b.ISBN = b1.ISBN
-		} else if override && b.compareIdentifierWith(b1) != AreTheSame {
-			b.ReportWarning("changed ISBN from %v to %v", b.ISBN, b1.ISBN)
-			b.AlternateISBN = append(b.AlternateISBN, b.ISBN) //TODO: create an addAlternateISBN that ensures no redundant ISBN are recorded
-			b.ISBN = b1.ISBN
-		} else if b.compareIdentifierWith(b1) != AreTheSame {
-			b.ReportWarning("found a different ISBN: %v (vs. %s)", b1.ISBN, b.ISBN)
-			b.AlternateISBN = append(b.AlternateISBN, b1.ISBN)
+		} else if override && b.compareISBNWith(b1) != AreTheSame {
+			if b.areOtherEditions(b.ISBN, b1.ISBN) {
+				Verbose.Printf("changed ISBN from %v (%s) to %v (%s)", b.ISBN, b.ISBNFormat[b.ISBN], b1.ISBN, b.ISBNFormat[b1.ISBN])
+			} else {
+				b.ReportWarning("changed ISBN from %v to %v", b.ISBN, b1.ISBN)
+			}
+			b.replaceISBN(b1.ISBN)
+		} else if b.compareISBNWith(b1) != AreTheSame {
+			if b.areOtherEditions(b.ISBN, b1.ISBN) {
+				Verbose.Printf("found ISBN of the %s edition: %v (vs. %s)", b.ISBNFormat[b1.ISBN], b1.ISBN, b.ISBN)
+			} else {
+				b.ReportWarning("found a different ISBN: %v (vs. %s)", b1.ISBN, b.ISBN)
+			}
+			b.addAlternateISBN(b1.ISBN)
+		}
+	}
+
+	for _, isbn := range b1.AlternateISBN {
+		b.addAlternateISBN(isbn)
+	}
+	b.preferEbookISBN()
+
+	for scheme, id := range b1.Identifiers {
+		if b.Identifiers == nil {
+			b.Identifiers = make(map[string]string)
+		}
+
+		switch {
+		case b.Identifiers[scheme] == "":
+			Verbose.Printf("set empty %s identifier to %v", scheme, id)
+			b.Identifiers[scheme] = id
+		case b.Identifiers[scheme] == id:
+		case override:
+			b.ReportWarning("changed %s identifier from %v to %v", scheme, b.Identifiers[scheme], id)
+			b.Identifiers[scheme] = id
+		default:
+			b.ReportWarning("found a different %s identifier: %v (vs. %s)", scheme, id, b.Identifiers[scheme])
 		}
 	}
 
@@ -361,6 +638,16 @@ func (b *Book) MergeWith(b1 *Book, override bool) {
 		}
 	}
 
+	if b1.Edition != "" {
+		if b.Edition == "" {
+			Verbose.Printf("set empty Edition to %s", b1.Edition)
+			b.Edition = b1.Edition
+		} else if override && !strings.EqualFold(b.Edition, b1.Edition) {
+			Verbose.Printf("changed Edition from %v to %v", b.Edition, b1.Edition)
+			b.Edition = b1.Edition
+		}
+	}
+
 	if b1.PublishedDate != "" {
 		if b.PublishedDate == "" {
 			Verbose.Printf("set empty PublishedDate to %s", b1.PublishedDate)
@@ -378,6 +665,20 @@ func (b *Book) MergeWith(b1 *Book, override bool) {
 		}
 	}
 
+	if b1.OriginalPublishedDate != "" {
+		if b.OriginalPublishedDate == "" {
+			Verbose.Printf("set empty OriginalPublishedDate to %s", b1.OriginalPublishedDate)
+			b.OriginalPublishedDate = b1.OriginalPublishedDate
+		} else if override && b.OriginalPublishedDate != b1.OriginalPublishedDate {
+			if compareNormalizedDates(b.OriginalPublishedDate, b1.OriginalPublishedDate) < AreAlmostTheSame {
+				b.ReportWarning("changed OriginalPublishedDate from %v to %v", b.OriginalPublishedDate, b1.OriginalPublishedDate)
+			} else {
+				Verbose.Printf("changed OriginalPublishedDate from %v to %v", b.OriginalPublishedDate, b1.OriginalPublishedDate)
+			}
+			b.OriginalPublishedDate = b1.OriginalPublishedDate
+		}
+	}
+
 	if b1.Description != "" {
 		if b.Description == "" {
 			Verbose.Printf("set empty Description to %.12v", b1.Description)
@@ -418,6 +719,20 @@ func (b *Book) MergeWith(b1 *Book, override bool) {
 		}
 	}
 
+	if b1.OriginalTitle != "" {
+		if b.OriginalTitle == "" {
+			Verbose.Printf("set empty OriginalTitle to %v", b1.OriginalTitle)
+			b.OriginalTitle = b1.OriginalTitle
+		} else if override {
+			if compareNormalizedStrings(b.OriginalTitle, b1.OriginalTitle) < AreAlmostTheSame {
+				b.ReportWarning("changed OriginalTitle from %v to %v", b.OriginalTitle, b1.OriginalTitle)
+			} else {
+				Verbose.Printf("changed OriginalTitle from %v to %v", b.OriginalTitle, b1.OriginalTitle)
+			}
+			b.OriginalTitle = b1.OriginalTitle
+		}
+	}
+
 	if b1.Language != "" {
 		if b.Language == "" {
 			Verbose.Printf("set empty Language to %v", b1.Language)
@@ -428,6 +743,16 @@ func (b *Book) MergeWith(b1 *Book, override bool) {
 		}
 	}
 
+	if b1.OriginalLanguage != "" {
+		if b.OriginalLanguage == "" {
+			Verbose.Printf("set empty OriginalLanguage to %v", b1.OriginalLanguage)
+			b.OriginalLanguage = b1.OriginalLanguage
+		} else if override && !strings.EqualFold(b.OriginalLanguage, b1.OriginalLanguage) {
+			Verbose.Printf("changed OriginalLanguage from %v to %v", b.OriginalLanguage, b1.OriginalLanguage)
+			b.OriginalLanguage = b1.OriginalLanguage
+		}
+	}
+
 	if b1.PageCount != 0 {
 		if b.PageCount == 0 {
 			Verbose.Printf("set empty PageCount to %v", b1.PageCount)
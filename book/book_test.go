@@ -88,7 +88,7 @@ func TestNewFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "fr",
 				Report: NewReport(),
 			},
 		},
@@ -98,7 +98,8 @@ func TestNewFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Skywalker,Luke", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "fr",
+				FileAs: map[string]string{"Luke Skywalker": "Skywalker, Luke"},
 				Report: NewReport(),
 			},
 		},
@@ -108,7 +109,8 @@ func TestNewFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Skywalker,Luke et Mini MOI", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker", "Mini Moi"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker", "Mini Moi"}, PublishedDate: "1980", Language: "fr",
+				FileAs: map[string]string{"Luke Skywalker": "Skywalker, Luke"},
 				Report: NewReport(),
 			},
 		},
@@ -140,7 +142,7 @@ func TestCompleteFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "fr",
 				Report: NewReport(),
 			},
 		},
@@ -151,7 +153,7 @@ func TestCompleteFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père fouettard", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Subject: []string{"Biographie"}, Language: "FR",
+				Title: "Mon père fouettard", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Subject: []string{"Biographie"}, Language: "fr",
 				Report: NewReport(),
 			},
 		},
@@ -162,7 +164,7 @@ func TestCompleteFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Mini Moi"}, PublishedDate: "2002", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Mini Moi"}, PublishedDate: "2002", Language: "fr",
 				Report: NewReport(),
 			},
 		},
@@ -193,7 +195,7 @@ func TestReplaceFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "fr",
 				Report: NewReport(),
 			},
 		},
@@ -204,7 +206,7 @@ func TestReplaceFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, Subject: []string{"Biographie"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, Subject: []string{"Biographie"}, PublishedDate: "1980", Language: "fr",
 				Report: &Report{
 					Issues:       []string{"changed Title from La gloire de mon père to Mon père, ce héros"},
 					Warnings:     []string{},
@@ -219,7 +221,7 @@ func TestReplaceFromMap(t *testing.T) {
 				"Title": "Mon père, ce héros", "Authors": "Luke Skywalker", "PublishedDate": "1980", "Language": "FR",
 			},
 			&Book{
-				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "FR",
+				Title: "Mon père, ce héros", Authors: []string{"Luke Skywalker"}, PublishedDate: "1980", Language: "fr",
 				Report: &Report{
 					Issues:       []string{"changed Authors from [Mini Moi] to [Luke Skywalker]"},
 					Warnings:     []string{"changed PublishedDate from 2002 to 1980"},
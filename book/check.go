@@ -1,9 +1,20 @@
 package book
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
 	"io"
 	"io/fs"
 	"path/filepath"
+	"strings"
+
+	// Register supported cover image formats.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 
 	"github.com/pirmd/epub"
 	"golang.org/x/net/html/atom"
@@ -12,6 +23,24 @@ import (
 	"github.com/pirmd/libro/book/htmlutil"
 )
 
+var (
+	// CoverMinWidth is the minimal width in pixels below which a cover is
+	// considered of low quality.
+	CoverMinWidth = 500
+
+	// CoverMinHeight is the minimal height in pixels below which a cover is
+	// considered of low quality.
+	CoverMinHeight = 800
+
+	// CoverMinRatio and CoverMaxRatio define the range of acceptable cover's
+	// aspect ratio (height / width). Usual book's cover ratio is around 1.6.
+	CoverMinRatio, CoverMaxRatio = 1.2, 1.8
+
+	// CoverMaxSize is the size in bytes above which a cover is considered as
+	// uselessly huge.
+	CoverMaxSize int64 = 2 << 20
+)
+
 // CheckCompletness assesses whether Book has enough Metadata to be
 // identified by the end-user.
 func (b *Book) CheckCompleteness() error {
@@ -19,18 +48,26 @@ func (b *Book) CheckCompleteness() error {
 		b.ReportIssue("book has no Title or no Author")
 	}
 
-	if len(b.Authors) > 1 {
+	if len(b.Authors) > 1 && len(b.Translators) == 0 && len(b.Contributors) == 0 {
 		b.ReportWarning("book has several Authors. Some might be wrongly considered as book's creator.")
 	}
 
-	if b.ISBN == "" || len(b.AlternateISBN) > 0 {
+	// Alternate ISBN are expected when they are known to be attributed to
+	// other formats' editions of an ebook.
+	if b.ISBN == "" || (len(b.AlternateISBN) > 0 && !b.hasExplainedISBN()) {
 		b.ReportWarning("book ISBN is unknown or has alternate possible values.")
 	}
 
+	b.checkISBNLanguage()
+
 	if b.Publisher == "" || b.PublishedDate == "" {
 		b.ReportWarning("book has incomplete publishing information.")
 	}
 
+	if b.OriginalPublishedDate != "" && Year(b.OriginalPublishedDate) > b.PublishedYear() && b.PublishedDate != "" {
+		b.ReportWarning("book OriginalPublishedDate (%s) is later than its PublishedDate (%s).", b.OriginalPublishedDate, b.PublishedDate)
+	}
+
 	if (b.Series != "" && b.SeriesIndex == 0) ||
 		(b.SeriesIndex != 0 && b.Series == "") ||
 		(b.SeriesTitle != "" && (b.SeriesIndex == 0 || b.Series == "")) {
@@ -67,61 +104,420 @@ func (b *Book) CheckConformity() error {
 }
 
 // CheckContentSecurity verifies that Book's content does not contain unsafe
-// HTML.
+// HTML. Scripts are reported apart from other HTML/CSS security risks,
+// grouped by category (see htmlutil.ScriptCategory).
 func (b *Book) CheckContentSecurity() error {
 	// TODO: change logic for scanning: go through all content
 	// (epub.WalkReadingContent), record CSS linked by HTML content in the
 	// scanning process then scanCSS
 
+	e, err := epub.Open(b.Path)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	opf, err := e.Package()
+	if err != nil {
+		return err
+	}
+
+	// Scripts are reported apart, they should not be counted twice.
+	EPUBScanner := newEPUBScanner()
+	EPUBScanner.IgnoreScripts = true
+
+	var issues []string
+	var scripts []htmlutil.Script
+	for _, item := range opf.Manifest.Items {
+		if item.Href == "" || filepath.IsAbs(item.Href) {
+			continue
+		}
+
+		if isInProperties("scripted", item.Properties) {
+			Verbose.Printf("%s: declared as a scripted content document", item.Href)
+			scripts = append(scripts, htmlutil.Script{Category: htmlutil.ScriptedContent, Detail: item.Href})
+		}
+
+		if !isHTMLResource(item.Href) && !isCSSResource(item.Href) && !isSVGResource(item.Href) {
+			continue
+		}
+
+		Debug.Printf("scan resource: %s", item.Href)
+		itemIssues, itemScripts, err := scanItemSecurity(e, item.Href, EPUBScanner)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range itemIssues {
+			Verbose.Printf("%s: %s", item.Href, issue)
+		}
+		issues = append(issues, itemIssues...)
+
+		for _, script := range itemScripts {
+			Verbose.Printf("%s: %s", item.Href, script)
+		}
+		scripts = append(scripts, itemScripts...)
+	}
+
+	if len(scripts) > 0 {
+		b.ReportIssue("book's content contains scripts: %s", summarizeScripts(scripts))
+	}
+
+	if nb := len(issues); nb > 0 {
+		b.ReportIssue("book's content contains HTML/CSS with security risks: %d issues detected", nb)
+	}
+
+	return nil
+}
+
+// scanItemSecurity scans an EPUB's resource for security risks and scripts.
+func scanItemSecurity(e *epub.Epub, href string, s *htmlutil.Scanner) (issues []string, scripts []htmlutil.Script, err error) {
+	f, err := e.OpenItem(href)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case isHTMLResource(href):
+		if issues, err = s.Scan(bytes.NewReader(content)); err != nil {
+			return nil, nil, err
+		}
+		scripts, err = htmlutil.InspectScripts(bytes.NewReader(content))
+
+	case isCSSResource(href):
+		if issues, err = s.ScanCSS(bytes.NewReader(content)); err != nil {
+			return nil, nil, err
+		}
+		scripts, err = htmlutil.InspectCSSScripts(bytes.NewReader(content))
+
+	case isSVGResource(href):
+		scripts, err = htmlutil.InspectScripts(bytes.NewReader(content))
+	}
+
+	return issues, scripts, err
+}
+
+// summarizeScripts counts scripts by category.
+func summarizeScripts(scripts []htmlutil.Script) string {
+	count := make(map[htmlutil.ScriptCategory]int)
+	for _, s := range scripts {
+		count[s.Category]++
+	}
+
+	var summary []string
+	for c := htmlutil.InlineScript; c <= htmlutil.ScriptedContent; c++ {
+		if nb := count[c]; nb > 0 {
+			summary = append(summary, fmt.Sprintf("%s (%d)", c, nb))
+		}
+	}
+
+	return strings.Join(summary, ", ")
+}
+
+// newEPUBScanner creates the htmlutil.Scanner used to look for security risks
+// in EPUB's content.
+func newEPUBScanner() *htmlutil.Scanner {
 	// TODO: can we be more specific than allowing any attributes, then
 	// focusing only on detecting suspicious URL, JS or CSS?
-	EPUBScanner := htmlutil.NewPermissiveScanner()
-	EPUBScanner.AllowedTags[atom.Img] = []string{"src=__REL_URL", "*"}
+	s := htmlutil.NewPermissiveScanner()
+	s.AllowedTags[atom.Img] = []string{"src=__REL_URL", "*"}
 	// Add some specific tags and cie encountered in the wild
-	EPUBScanner.AllowedTags[atom.Meta] = append([]string{
+	s.AllowedTags[atom.Meta] = append([]string{
 		"http-equiv=Content-Style-Type",
-	}, EPUBScanner.AllowedTags[atom.Meta]...)
-	EPUBScanner.AllowedTags[atom.A] = append([]string{
+	}, s.AllowedTags[atom.Meta]...)
+	s.AllowedTags[atom.A] = append([]string{
 		"tag=**",
-	}, EPUBScanner.AllowedTags[atom.A]...)
-	EPUBScanner.AllowedTags[atom.Link] = append([]string{
+	}, s.AllowedTags[atom.A]...)
+	s.AllowedTags[atom.Link] = append([]string{
 		"tag=**",
-	}, EPUBScanner.AllowedTags[atom.Link]...)
+	}, s.AllowedTags[atom.Link]...)
+	// SVG (notably used for cover pages) and MathML are legitimate EPUB3
+	// content.
+	s.AllowedTags[atom.Svg] = []string{}
+	s.AllowedTags[atom.Math] = []string{}
+
+	return s
+}
+
+// isHTMLResource reports whether an EPUB's resource is an HTML document.
+func isHTMLResource(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".html", ".htm", ".xhtml":
+		return true
+	}
+	return false
+}
+
+// isSVGResource reports whether an EPUB's resource is an SVG image.
+func isSVGResource(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".svg"
+}
+
+// isCSSResource reports whether an EPUB's resource is a CSS style sheet.
+func isCSSResource(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".css"
+}
+
+// accessibilityMetadata lists the schema.org metadata expected to describe
+// the accessibility of an EPUB.
+var accessibilityMetadata = []string{
+	"schema:accessMode",
+	"schema:accessibilityFeature",
+	"schema:accessibilitySummary",
+}
+
+// CheckAccessibility verifies that Book's content can be accessed by visually
+// impaired readers. It looks for images without alternate text, content
+// without language declaration, skipped heading levels, tables without
+// headers, lacking navigation landmarks or page-list and lacking schema.org
+// accessibility metadata.
+func (b *Book) CheckAccessibility() error {
+	e, err := epub.Open(b.Path)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	opf, err := e.Package()
+	if err != nil {
+		return err
+	}
 
 	var issues []string
-	if err := epub.WalkPublicationResources(b.Path, func(r io.Reader, fi fs.FileInfo) error {
-		switch filepath.Ext(fi.Name()) {
-		case ".html", ".HTML", ".htm", ".HTM":
-			Debug.Printf("scan HTML resources: %s", fi.Name())
-			if HTMLissues, err := EPUBScanner.Scan(r); err != nil {
-				return err
-			} else if len(HTMLissues) > 0 {
-				issues = append(issues, HTMLissues...)
-			}
+	var hasLandmarks, hasPageList bool
+	for _, item := range opf.Manifest.Items {
+		if item.MediaType != "application/xhtml+xml" || item.Href == "" || filepath.IsAbs(item.Href) {
+			continue
+		}
+
+		Debug.Printf("inspect accessibility of HTML resources: %s", item.Href)
+		a, err := inspectItemAccessibility(e, item.Href)
+		if err != nil {
+			return err
+		}
+
+		for _, issue := range a.Issues {
+			Verbose.Printf("%s: %s", item.Href, issue)
+			issues = append(issues, issue)
+		}
+
+		if isInProperties("nav", item.Properties) {
+			hasLandmarks, hasPageList = a.Landmarks, a.PageList
+		}
+	}
+
+	// EPUB2 provides landmarks using the package document's guide and
+	// page-list using the NCX's pageList.
+	if !hasLandmarks {
+		if hasLandmarks, err = hasEPUB2Guide(e); err != nil {
+			return err
+		}
+	}
+
+	if !hasPageList {
+		if hasPageList, err = hasNCXPageList(e, opf); err != nil {
+			return err
+		}
+	}
+
+	if nb := len(issues); nb > 0 {
+		b.ReportIssue("book's content has accessibility shortcomings: %d issues detected", nb)
+	}
+
+	if !hasLandmarks {
+		b.ReportWarning("book has no navigation landmarks.")
+	}
+
+	if !hasPageList {
+		b.ReportWarning("book has no page-list.")
+	}
+
+	var missing []string
+	for _, m := range accessibilityMetadata {
+		if !hasMetadata(opf.Metadata, m) {
+			missing = append(missing, m)
+		}
+	}
+	if len(missing) > 0 {
+		b.ReportWarning("book has no accessibility metadata (missing %s).", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+func inspectItemAccessibility(e *epub.Epub, href string) (*htmlutil.Accessibility, error) {
+	r, err := e.OpenItem(href)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return htmlutil.InspectAccessibility(r)
+}
+
+// hasEPUB2Guide checks whether EPUB's package document has a (legacy) guide
+// that lists at least one reference.
+func hasEPUB2Guide(e *epub.Epub) (bool, error) {
+	var container struct {
+		Rootfile struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeZipXML(e, "META-INF/container.xml", &container); err != nil {
+		return false, err
+	}
+
+	var opf struct {
+		References []struct{} `xml:"guide>reference"`
+	}
+	if err := decodeZipXML(e, container.Rootfile.FullPath, &opf); err != nil {
+		return false, err
+	}
+
+	return len(opf.References) > 0, nil
+}
+
+// hasNCXPageList checks whether EPUB's NCX (EPUB2 table of content) has a
+// pageList that lists at least one page.
+func hasNCXPageList(e *epub.Epub, opf *epub.PackageDocument) (bool, error) {
+	for _, item := range opf.Manifest.Items {
+		if item.MediaType != "application/x-dtbncx+xml" || item.Href == "" || filepath.IsAbs(item.Href) {
+			continue
+		}
+
+		r, err := e.OpenItem(item.Href)
+		if err != nil {
+			return false, err
+		}
+
+		var ncx struct {
+			PageTargets []struct{} `xml:"pageList>pageTarget"`
+		}
+		err = xml.NewDecoder(r).Decode(&ncx)
+		r.Close()
+		if err != nil {
+			return false, fmt.Errorf("fail to read NCX '%s': %v", item.Href, err)
+		}
+
+		if len(ncx.PageTargets) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// decodeZipXML decodes the XML document stored in an EPUB's file.
+func decodeZipXML(e *epub.Epub, name string, v interface{}) error {
+	r, err := e.Open(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
 
-		case ".css", ".CSS":
-			Debug.Printf("scan CSS resources: %s", fi.Name())
-			if CSSissues, err := EPUBScanner.ScanCSS(r); err != nil {
-				return err
-			} else if len(CSSissues) > 0 {
-				issues = append(issues, CSSissues...)
-			}
+	if err := xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("fail to read '%s': %v", name, err)
+	}
+	return nil
+}
 
-		default:
-			return nil
+// isInProperties checks whether property is listed in a space-separated list
+// of properties.
+func isInProperties(property string, properties string) bool {
+	for _, p := range strings.Fields(properties) {
+		if p == property {
+			return true
 		}
+	}
+	return false
+}
 
-		for _, issue := range issues {
-			Verbose.Print(issue)
+// hasMetadata checks whether a metadata exists either as an EPUB3 property
+// or as an EPUB2 named meta.
+func hasMetadata(mdata *epub.Metadata, name string) bool {
+	for _, m := range mdata.Meta {
+		if m.Name == name || (m.Meta != nil && m.Property == name) {
+			return true
 		}
+	}
+	return false
+}
 
+// CheckCover verifies that Book has a cover of reasonable quality: cover
+// shall exist in the EPUB archive, be of a sufficient resolution with a usual
+// book's aspect ratio (unless it is an SVG image) and shall not be uselessly
+// huge.
+func (b *Book) CheckCover() error {
+	e, err := epub.Open(b.Path)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	opf, err := e.Package()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range unknownCoverItems(opf) {
+		b.ReportIssue("book's cover meta points to unknown manifest item '%s'.", id)
+	}
+
+	href, mediaType, err := findCover(e, opf)
+	if err == ErrNoCover {
+		b.ReportIssue("book has no cover.")
 		return nil
-	}); err != nil {
+	}
+	if err != nil {
 		return err
 	}
 
-	if nb := len(issues); nb > 0 {
-		b.ReportIssue("book's content contains HTML/CSS with security risks: %d issues detected", nb)
+	r, err := e.OpenItem(href)
+	if errors.Is(err, fs.ErrNotExist) {
+		b.ReportIssue("book's cover (%s) is declared but missing from EPUB.", href)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	fi, err := r.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() > CoverMaxSize {
+		b.ReportIssue("book's cover is huge (%.1f MB).", float64(fi.Size())/(1<<20))
+	}
+
+	// SVG covers are vector images, their resolution and aspect ratio are not
+	// relevant.
+	if mediaType == "image/svg+xml" {
+		Debug.Printf("cover is an SVG image")
+		return nil
+	}
+
+	cfg, format, err := image.DecodeConfig(r)
+	if err != nil {
+		b.ReportIssue("book's cover is not a recognized image: %v", err)
+		return nil
+	}
+	Debug.Printf("cover is a %dx%d %s image", cfg.Width, cfg.Height, format)
+
+	if cfg.Width < CoverMinWidth || cfg.Height < CoverMinHeight {
+		b.ReportIssue("book's cover has a low resolution (%dx%d).", cfg.Width, cfg.Height)
+	}
+
+	if cfg.Width > 0 {
+		if ratio := float64(cfg.Height) / float64(cfg.Width); ratio < CoverMinRatio || ratio > CoverMaxRatio {
+			b.ReportIssue("book's cover has an unusual aspect ratio (%.2f).", ratio)
+		}
 	}
 
 	return nil
@@ -0,0 +1,60 @@
+package book
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckContentSecurity(t *testing.T) {
+	scripted := filepath.Join(t.TempDir(), "scripted.epub")
+	if err := injectInEpub(scripted, filepath.Join(testdataBooks, "pg6099.epub"), map[string][2]string{
+		"OEBPS/wrap0000.html": {"</body>", `<script>alert('XSS')</script><p onclick="alert('XSS')">Hello</p></body>`},
+		"OEBPS/content.opf":   {`id="coverpage-wrapper"`, `id="coverpage-wrapper" properties="scripted"`},
+	}); err != nil {
+		t.Fatalf("Fail to prepare scripted EPUB: %v", err)
+	}
+
+	dodgyCSS := filepath.Join(t.TempDir(), "css.epub")
+	if err := injectInEpub(dodgyCSS, filepath.Join(testdataBooks, "pg6099.epub"), map[string][2]string{
+		"OEBPS/pgepub.css": {"", "p { background: url(http://ha.ckers.org/xss.png) }\n"},
+	}); err != nil {
+		t.Fatalf("Fail to prepare EPUB with unsafe CSS: %v", err)
+	}
+
+	testCases := []struct {
+		path string
+		want []string
+	}{
+		{
+			path: filepath.Join(testdataBooks, "pg6099.epub"),
+			want: nil,
+		},
+		{
+			path: scripted,
+			want: []string{
+				"book's content contains scripts: inline script (1), event handler (1), scripted content document (1)",
+			},
+		},
+		{
+			path: dodgyCSS,
+			want: []string{
+				"book's content contains HTML/CSS with security risks: 1 issues detected",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		b := New()
+		b.Path = tc.path
+
+		if err := b.CheckContentSecurity(); err != nil {
+			t.Errorf("Fail to check content security of %s: %v", tc.path, err)
+			continue
+		}
+
+		if fmt.Sprint(b.Issues) != fmt.Sprint(tc.want) {
+			t.Errorf("Check of %s content security failed.\nWant: %v\nGot : %v", filepath.Base(tc.path), tc.want, b.Issues)
+		}
+	}
+}
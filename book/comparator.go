@@ -0,0 +1,432 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// List of Book's fields assessed by a Comparator.
+const (
+	// FieldISBN compares Books' ISBN, AlternateISBN and Identifiers.
+	FieldISBN = "ISBN"
+	// FieldTitle compares Books' Title and SubTitle.
+	FieldTitle = "Title"
+	// FieldAuthors compares Books' Authors.
+	FieldAuthors = "Authors"
+	// FieldPublisher compares Books' Publisher.
+	FieldPublisher = "Publisher"
+	// FieldPublishedDate compares Books' PublishedDate or
+	// OriginalPublishedDate.
+	FieldPublishedDate = "PublishedDate"
+)
+
+// comparedFields lists the Book's fields assessed by a Comparator.
+var comparedFields = []string{FieldISBN, FieldTitle, FieldAuthors, FieldPublisher, FieldPublishedDate}
+
+var (
+	// DefaultComparator is the Comparator used by Book.CompareWith.
+	DefaultComparator = NewComparator()
+)
+
+// Thresholds defines the minimum similarity (between 0 and 1) above which two
+// elements are considered the same, almost the same or maybe the same.
+type Thresholds struct {
+	Same       float64 `json:"same"`
+	AlmostSame float64 `json:"almost_same"`
+	MaybeSame  float64 `json:"maybe_same"`
+}
+
+// Level returns the SimilarityLevel corresponding to a similarity.
+func (t Thresholds) Level(similarity float64) SimilarityLevel {
+	switch {
+	case similarity > t.Same:
+		return AreTheSame
+	case similarity > t.AlmostSame:
+		return AreAlmostTheSame
+	case similarity > t.MaybeSame:
+		return AreMaybeTheSame
+	}
+
+	return AreNotTheSame
+}
+
+// listLevel returns the SimilarityLevel of two lists given their Jaccard-like
+// index and the coverage of the shortest list by the other one.
+func (t Thresholds) listLevel(jaccard, coverage float64) SimilarityLevel {
+	switch {
+	case jaccard > t.Same:
+		return AreTheSame
+	case jaccard > t.AlmostSame, coverage > t.Same:
+		return AreAlmostTheSame
+	case jaccard > t.MaybeSame, coverage > t.AlmostSame:
+		return AreMaybeTheSame
+	}
+
+	return AreNotTheSame
+}
+
+// Comparator assesses the similarity between two Books.
+//
+// Books are considered the same when their ISBN are the same and their names
+// (Title, then Authors, then Publisher and PublishedDate) are similar enough.
+// Each field's similarity is converted into a SimilarityLevel according to
+// Thresholds. Weights tune each field's importance in the overall Score of
+// the comparison that settles cases where fields disagree, a field whose
+// weight is zero is not compared.
+type Comparator struct {
+	// Thresholds defines the minimum similarity above which two fields are
+	// considered the same, almost the same or maybe the same.
+	// Default to 0.9, 0.8 and 0.7.
+	Thresholds Thresholds `json:"thresholds"`
+
+	// Weights defines the importance of each field (like "Title" or
+	// "Authors") in the overall Score of the comparison.
+	// Default to 3 for ISBN, 2 for Title and Authors, 1 for Publisher and
+	// PublishedDate.
+	Weights map[string]float64 `json:"weights"`
+
+	// Transliterate, if set, compares Cyrillic or Greek strings using their
+	// Latin transliteration so that they match their transliterated version
+	// from another source.
+	// Default to true.
+	Transliterate bool `json:"transliterate"`
+}
+
+// NewComparator creates a new Comparator with default settings.
+func NewComparator() *Comparator {
+	return &Comparator{
+		Thresholds: Thresholds{
+			Same:       isAreTheSameThreshold,
+			AlmostSame: isAreAlmostTheSameThreshold,
+			MaybeSame:  isAreMaybeTheSameThreshold,
+		},
+		Weights: map[string]float64{
+			FieldISBN:          3,
+			FieldTitle:         2,
+			FieldAuthors:       2,
+			FieldPublisher:     1,
+			FieldPublishedDate: 1,
+		},
+		Transliterate: true,
+	}
+}
+
+// LoadComparator reads Comparator's settings from a JSON file and uses them
+// for DefaultComparator. Settings that are not specified keep their default
+// value.
+func LoadComparator(path string) error {
+	//#nosec G304 -- path is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	c := NewComparator()
+	if err := json.NewDecoder(f).Decode(c); err != nil {
+		return fmt.Errorf("fail to decode comparator settings from %s: %v", path, err)
+	}
+
+	if t := c.Thresholds; !(0 <= t.MaybeSame && t.MaybeSame <= t.AlmostSame && t.AlmostSame <= t.Same && t.Same <= 1) {
+		return fmt.Errorf("invalid thresholds in %s: 0 <= maybe_same <= almost_same <= same <= 1 is expected", path)
+	}
+
+	for field, w := range c.Weights {
+		if !isInList(field, comparedFields) {
+			return fmt.Errorf("invalid weighted field in %s: '%s' is not one of %s", path, field, strings.Join(comparedFields, ", "))
+		}
+		if w < 0 {
+			return fmt.Errorf("invalid weight for %s in %s: weight shall not be negative", field, path)
+		}
+	}
+
+	DefaultComparator = c
+	return nil
+}
+
+// FieldComparison details the comparison of one of Books' fields.
+type FieldComparison struct {
+	// Field is the name of the compared field.
+	Field string
+
+	// Values are the normalized values of the field that are compared.
+	Values [2]string
+
+	// Similarity is the similarity (between 0 and 1) of the values.
+	Similarity float64
+
+	// Level is the SimilarityLevel of the values.
+	Level SimilarityLevel
+
+	// Weight is the importance of the field in the comparison's Score.
+	Weight float64
+}
+
+// Comparison details the assessment of two Books' similarity.
+type Comparison struct {
+	// Level is the SimilarityLevel of the Books.
+	Level SimilarityLevel
+
+	// Rationale is a short explanation of Level.
+	Rationale string
+
+	// Score is the weighted average of comparable fields' Similarity.
+	Score float64
+
+	// Fields details the comparison of each field.
+	Fields []FieldComparison
+}
+
+// Compare assesses the similarity level between two Books.
+func (c *Comparator) Compare(b, b1 *Book) *Comparison {
+	cmp := &Comparison{
+		Fields: []FieldComparison{
+			c.compareISBN(b, b1),
+			c.compareStrings(FieldTitle, titleOf(b), titleOf(b1)),
+			c.compareLists(FieldAuthors, authorsIdentity(b.Authors), authorsIdentity(b1.Authors)),
+			c.compareStrings(FieldPublisher, b.Publisher, b1.Publisher),
+			c.comparePublishedDates(b, b1),
+		},
+	}
+
+	var sumW float64
+	for _, f := range cmp.Fields {
+		if f.Level != AreNotComparable {
+			cmp.Score += f.Weight * f.Similarity
+			sumW += f.Weight
+		}
+	}
+	if sumW > 0 {
+		cmp.Score /= sumW
+	}
+
+	cmp.Level, cmp.Rationale = cmp.decide(c.Thresholds)
+	return cmp
+}
+
+// Field returns the comparison of the given field.
+func (cmp Comparison) Field(name string) FieldComparison {
+	for _, f := range cmp.Fields {
+		if f.Field == name {
+			return f
+		}
+	}
+	return FieldComparison{Field: name}
+}
+
+// decide determines the Books' SimilarityLevel from their fields' one. When
+// fields disagree, that is when Books are almost or maybe the same, the
+// comparison's Score settles it: a Score above the AlmostSame threshold makes
+// them almost the same whereas a Score below the MaybeSame threshold makes
+// them maybe the same.
+func (cmp Comparison) decide(t Thresholds) (SimilarityLevel, string) {
+	lvl, rational := cmp.decideFields()
+
+	switch {
+	case lvl == AreMaybeTheSame && cmp.Score > t.AlmostSame:
+		return AreAlmostTheSame, fmt.Sprintf("%s, weighted score is %.2f", rational, cmp.Score)
+	case lvl == AreAlmostTheSame && cmp.Score <= t.MaybeSame:
+		return AreMaybeTheSame, fmt.Sprintf("%s, weighted score is %.2f", rational, cmp.Score)
+	}
+
+	return lvl, rational
+}
+
+// decideFields determines the Books' SimilarityLevel from their ISBN and
+// names' one.
+func (cmp Comparison) decideFields() (SimilarityLevel, string) {
+	isbnLvl := cmp.Field(FieldISBN).Level
+	nameLvl, nameRational := cmp.decideName()
+
+	rational := fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
+
+	switch isbnLvl {
+	case AreTheSame:
+		switch nameLvl {
+		case AreTheSame:
+			return AreTheSame, rational
+		case AreAlmostTheSame, AreNotComparable:
+			return AreAlmostTheSame, rational
+		}
+		return AreMaybeTheSame, rational
+
+	case AreNotTheSame:
+		switch nameLvl {
+		case AreTheSame, AreAlmostTheSame:
+			return AreMaybeTheSame, rational
+		default:
+			return AreNotTheSame, rational
+		}
+
+	default:
+		return nameLvl, rational
+	}
+}
+
+// decideName determines the similarity of Books' names, that is their
+// Title, Authors and Publication.
+func (cmp Comparison) decideName() (SimilarityLevel, string) {
+	lvl := cmp.Field(FieldTitle).Level
+
+	if lvl >= AreAlmostTheSame {
+		if authLvl := cmp.Field(FieldAuthors).Level; authLvl >= AreAlmostTheSame {
+			if pubLvl := cmp.decidePublication(); pubLvl >= AreAlmostTheSame {
+				return AreTheSame, fmt.Sprintf("Titles are %s, Authors are %s, Publication are %s", lvl, authLvl, pubLvl)
+			}
+			return AreAlmostTheSame, fmt.Sprintf("Titles are %s, Authors are %s", lvl, authLvl)
+		}
+		return AreMaybeTheSame, fmt.Sprintf("Titles are %s", lvl)
+	}
+
+	return lvl, fmt.Sprintf("Titles are %s", lvl)
+}
+
+func (cmp Comparison) decidePublication() SimilarityLevel {
+	lvl := cmp.Field(FieldPublisher).Level
+	if lvl >= AreAlmostTheSame {
+		if cmp.Field(FieldPublishedDate).Level >= AreAlmostTheSame {
+			return AreTheSame
+		}
+		return AreAlmostTheSame
+	}
+
+	return lvl
+}
+
+// newFieldComparison prepares the comparison of a field. Fields whose weight
+// is zero are considered not comparable.
+func (c *Comparator) newFieldComparison(field string, v, v1 string) (FieldComparison, bool) {
+	w, exists := c.Weights[field]
+	if !exists {
+		w = 1
+	}
+	return FieldComparison{Field: field, Values: [2]string{v, v1}, Weight: w}, w > 0
+}
+
+func (c *Comparator) compareStrings(field string, s, s1 string) FieldComparison {
+	ns, ns1 := c.normalize(s), c.normalize(s1)
+
+	f, ok := c.newFieldComparison(field, strings.TrimSpace(ns), strings.TrimSpace(ns1))
+	if !ok {
+		return f
+	}
+
+	if dist, ok := stringsSimilarity(ns, ns1); ok {
+		f.Similarity, f.Level = dist, c.Thresholds.Level(dist)
+	}
+	return f
+}
+
+func (c *Comparator) compareLists(field string, l, l1 []string) FieldComparison {
+	nl, nl1 := c.normalizeList(l), c.normalizeList(l1)
+
+	f, ok := c.newFieldComparison(field, joinList(nl), joinList(nl1))
+	if !ok {
+		return f
+	}
+
+	if jaccard, coverage, ok := listsSimilarity(nl, nl1, c.Thresholds); ok {
+		f.Similarity, f.Level = jaccard, c.Thresholds.listLevel(jaccard, coverage)
+	}
+	return f
+}
+
+func (c *Comparator) compareDates(field string, d, d1 string) FieldComparison {
+	f, ok := c.newFieldComparison(field, d, d1)
+	if !ok {
+		return f
+	}
+
+	switch f.Level = compareNormalizedDates(d, d1); f.Level {
+	case AreTheSame:
+		f.Similarity = 1
+	case AreAlmostTheSame:
+		f.Similarity = c.Thresholds.Same
+	case AreMaybeTheSame:
+		f.Similarity = c.Thresholds.AlmostSame
+	}
+	return f
+}
+
+// comparePublishedDates compares Books' PublishedDate. Books that are
+// different editions of the same work (like a 2014 paperback of a 1959 novel)
+// are compared using the date of the original publication of one or both of
+// them, the most similar dates being retained.
+func (c *Comparator) comparePublishedDates(b, b1 *Book) FieldComparison {
+	best := c.compareDates(FieldPublishedDate, b.PublishedDate, b1.PublishedDate)
+	if best.Level == AreTheSame || (b.OriginalPublishedDate == "" && b1.OriginalPublishedDate == "") {
+		return best
+	}
+
+	for _, dates := range [][2]string{
+		{b.OriginalPublishedDate, b1.OriginalPublishedDate},
+		{b.OriginalPublishedDate, b1.PublishedDate},
+		{b.PublishedDate, b1.OriginalPublishedDate},
+	} {
+		if f := c.compareDates(FieldPublishedDate, dates[0], dates[1]); f.Level > best.Level {
+			best = f
+		}
+	}
+
+	return best
+}
+
+func (c *Comparator) compareISBN(b, b1 *Book) FieldComparison {
+	f, ok := c.newFieldComparison(FieldISBN, b.ISBN, b1.ISBN)
+	if !ok {
+		return f
+	}
+
+	switch f.Level = b.compareIdentifierWith(b1); f.Level {
+	case AreTheSame:
+		f.Similarity = 1
+	case AreAlmostTheSame:
+		f.Similarity = c.Thresholds.Same
+	case AreMaybeTheSame:
+		f.Similarity = c.Thresholds.AlmostSame
+	}
+	return f
+}
+
+// normalize outputs a normalized version of a string to ease its comparison.
+func (c *Comparator) normalize(s string) string {
+	if c.Transliterate {
+		s = Transliterate(s)
+	}
+	return NormalizeString(s)
+}
+
+// normalizeList normalizes each element of a list of strings and splits them
+// into words. Empty elements are ignored.
+func (c *Comparator) normalizeList(l []string) [][]string {
+	var normalized [][]string
+	for _, s := range l {
+		if words := strings.Fields(c.normalize(s)); len(words) > 0 {
+			normalized = append(normalized, words)
+		}
+	}
+	return normalized
+}
+
+// titleOf returns the full title of a Book, that is its Title and SubTitle or
+// SeriesTitle if no Title is known.
+func titleOf(b *Book) string {
+	t := b.Title
+	if b.SubTitle != "" {
+		t += " " + b.SubTitle
+	}
+	if t == "" {
+		t = b.SeriesTitle
+	}
+	return t
+}
+
+func joinList(l [][]string) string {
+	s := make([]string, len(l))
+	for i, words := range l {
+		s[i] = strings.Join(words, " ")
+	}
+	return strings.Join(s, "; ")
+}
@@ -0,0 +1,129 @@
+package book
+
+import (
+	"testing"
+)
+
+func TestComparatorCompare(t *testing.T) {
+	b := &Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll"}, ISBN: "9780141439761", Publisher: "Penguin Classics", PublishedDate: "2003-02-04"}
+
+	testCases := []struct {
+		in        *Book
+		wantLevel SimilarityLevel
+		wantField map[string]SimilarityLevel
+	}{
+		{
+			&Book{Title: "Alice's adventures in wonderland", Authors: []string{"Lewis Carroll"}, ISBN: "9780141439761", Publisher: "Penguin", PublishedDate: "2003"},
+			AreTheSame,
+			map[string]SimilarityLevel{FieldISBN: AreTheSame, FieldTitle: AreTheSame, FieldAuthors: AreTheSame, FieldPublisher: AreAlmostTheSame, FieldPublishedDate: AreAlmostTheSame},
+		},
+		{
+			&Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll", "John Tenniel"}},
+			AreAlmostTheSame,
+			map[string]SimilarityLevel{FieldISBN: AreNotComparable, FieldTitle: AreTheSame, FieldAuthors: AreAlmostTheSame, FieldPublisher: AreNotComparable},
+		},
+		{
+			&Book{Title: "Vingt mille lieues sous les mers", Authors: []string{"Jules Verne"}, ISBN: "9782253006329"},
+			AreNotTheSame,
+			map[string]SimilarityLevel{FieldISBN: AreNotTheSame, FieldTitle: AreNotTheSame, FieldAuthors: AreNotTheSame},
+		},
+	}
+
+	for _, tc := range testCases {
+		got := NewComparator().Compare(b, tc.in)
+
+		if got.Level != tc.wantLevel {
+			t.Errorf("Comparing %+v failed.\nWant: %v\nGot : %v (%s)", tc.in, tc.wantLevel, got.Level, got.Rationale)
+		}
+
+		for field, want := range tc.wantField {
+			if f := got.Field(field); f.Level != want {
+				t.Errorf("Comparing %s of %+v failed.\nWant: %v\nGot : %v (%+v)", field, tc.in, want, f.Level, f)
+			}
+		}
+	}
+}
+
+func TestComparatorWeights(t *testing.T) {
+	b := &Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll"}}
+
+	testCases := []struct {
+		in      *Book
+		weights map[string]float64
+		want    SimilarityLevel
+	}{
+		{
+			&Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Charles Dodgson"}},
+			nil,
+			AreMaybeTheSame,
+		},
+		{
+			&Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Charles Dodgson"}},
+			map[string]float64{FieldTitle: 8, FieldAuthors: 1},
+			AreAlmostTheSame,
+		},
+		{
+			&Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll", "John Tenniel"}},
+			nil,
+			AreAlmostTheSame,
+		},
+		{
+			&Book{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll", "John Tenniel"}},
+			map[string]float64{FieldTitle: 1, FieldAuthors: 8},
+			AreMaybeTheSame,
+		},
+	}
+
+	for _, tc := range testCases {
+		c := NewComparator()
+		for field, w := range tc.weights {
+			c.Weights[field] = w
+		}
+
+		if got := c.Compare(b, tc.in); got.Level != tc.want {
+			t.Errorf("Comparing %+v with weights %v failed.\nWant: %v\nGot : %v (%s, score: %.2f)", tc.in, tc.weights, tc.want, got.Level, got.Rationale, got.Score)
+		}
+	}
+}
+
+func TestComparePublishedDates(t *testing.T) {
+	testCases := []struct {
+		b, b1 *Book
+		want  SimilarityLevel
+	}{
+		{&Book{PublishedDate: "2014"}, &Book{PublishedDate: "1960"}, AreNotTheSame},
+		{&Book{PublishedDate: "2014", OriginalPublishedDate: "1960"}, &Book{PublishedDate: "1960-07-11"}, AreAlmostTheSame},
+		{&Book{PublishedDate: "2014", OriginalPublishedDate: "1960"}, &Book{PublishedDate: "2006", OriginalPublishedDate: "1960"}, AreTheSame},
+		{&Book{PublishedDate: "2014", OriginalPublishedDate: "1960"}, &Book{PublishedDate: "2006"}, AreNotTheSame},
+	}
+
+	for _, tc := range testCases {
+		if got := NewComparator().comparePublishedDates(tc.b, tc.b1); got.Level != tc.want {
+			t.Errorf("Comparing dates of %+v and %+v failed.\nWant: %v\nGot : %v", tc.b, tc.b1, tc.want, got.Level)
+		}
+	}
+}
+
+func TestLoadComparator(t *testing.T) {
+	defer func() { DefaultComparator = NewComparator() }()
+
+	for _, invalid := range []string{"testdata/comparator/invalid.json", "testdata/comparator/invalid_field.json", "testdata/comparator/invalid_weight.json"} {
+		if err := LoadComparator(invalid); err == nil {
+			t.Errorf("Loading invalid comparator settings from %s should fail", invalid)
+		}
+	}
+
+	if err := LoadComparator("testdata/comparator/comparator.json"); err != nil {
+		t.Fatalf("fail to load comparator settings: %v", err)
+	}
+
+	if DefaultComparator.Thresholds.Same != 0.95 || !DefaultComparator.Transliterate {
+		t.Errorf("Loading comparator settings failed.\nWant: thresholds from file and default transliteration\nGot : %+v", DefaultComparator)
+	}
+
+	b := &Book{Title: "Alice's Adventures in Wonderland", Publisher: "Penguin Classics"}
+	b1 := &Book{Title: "Alice's Adventures in Wonderland", Publisher: "Penguin"}
+	if f := DefaultComparator.Compare(b, b1).Field(FieldPublisher); f.Level != AreNotComparable {
+		t.Errorf("Comparing Publisher whose weight is 0 failed.\nWant: %v\nGot : %v", AreNotComparable, f.Level)
+	}
+}
@@ -1,11 +1,12 @@
 package book
 
 import (
-	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/runes"
@@ -45,6 +46,10 @@ var (
 	// reMeaningless regexp that identifies meaningless part of a string
 	// (punctuation for example)
 	reMeaningless = regexp.MustCompile(`[^\p{Ll}\p{Lm}\p{Lo}\p{Lt}\p{Lu}\p{Nd}\p{Nl}\p{No}\p{Sc}\p{Sm}]`)
+
+	// foldReplacer folds case-folded letters that are not decomposed by
+	// Unicode normalization into their usual Latin equivalent.
+	foldReplacer = strings.NewReplacer("ı", "i", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th")
 )
 
 // String outputs a human understandable description of a SimilarityLevel.
@@ -52,36 +57,40 @@ func (lvl SimilarityLevel) String() string {
 	return [...]string{"not comparable", "not the same", "maybe the same", "almost the same", "the same"}[lvl]
 }
 
+// MarshalText outputs a human understandable description of a
+// SimilarityLevel when serializing it.
+func (lvl SimilarityLevel) MarshalText() ([]byte, error) {
+	return []byte(lvl.String()), nil
+}
+
 // CompareWith assesses the similarity level between two books with a short
-// explanation of the rational.
+// explanation of the rational, using DefaultComparator.
 func (b Book) CompareWith(b1 *Book) (SimilarityLevel, string) {
-	isbnLvl := b.compareIdentifierWith(b1)
-	nameLvl, nameRational := b.compareNameWith(b1)
-
-	switch isbnLvl {
-	case AreTheSame:
-		switch nameLvl {
-		case AreTheSame:
-			return AreTheSame, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
-		case AreAlmostTheSame, AreNotComparable:
-			return AreAlmostTheSame, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
-		}
-		return AreMaybeTheSame, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
+	cmp := DefaultComparator.Compare(&b, b1)
+	return cmp.Level, cmp.Rationale
+}
 
-	case AreNotTheSame:
-		switch nameLvl {
-		case AreTheSame, AreAlmostTheSame:
-			return AreMaybeTheSame, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
-		default:
-			return AreNotTheSame, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
-		}
+// compareIdentifierWith compares Books' ISBN and AlternateISBN then, if they
+// are not enough to identify Books, Books' other Identifiers.
+func (b Book) compareIdentifierWith(b1 *Book) SimilarityLevel {
+	lvl := b.compareISBNWith(b1)
+	if lvl >= AreAlmostTheSame {
+		return lvl
+	}
 
-	default:
-		return nameLvl, fmt.Sprintf("ISBN are %s, %s", isbnLvl, nameRational)
+	if compareIdentifiers(b.Identifiers, b1.Identifiers) == AreTheSame {
+		if lvl == AreNotTheSame {
+			// Books share an identifier but ISBN are different (like for a
+			// re-edition)
+			return AreAlmostTheSame
+		}
+		return AreTheSame
 	}
+
+	return lvl
 }
 
-func (b Book) compareIdentifierWith(b1 *Book) SimilarityLevel {
+func (b Book) compareISBNWith(b1 *Book) SimilarityLevel {
 	lvl := compareNormalizedISBN(b.ISBN, b1.ISBN)
 	if lvl == AreTheSame {
 		return lvl
@@ -107,56 +116,23 @@ func (b Book) compareIdentifierWith(b1 *Book) SimilarityLevel {
 	return lvl
 }
 
-func (b Book) compareNameWith(b1 *Book) (SimilarityLevel, string) {
-	lvl := b.compareTitlesWith(b1)
-
-	if lvl >= AreAlmostTheSame {
-		if authLvl := b.compareAuthorsWith(b1); authLvl >= AreAlmostTheSame {
-			if pubLvl := b.comparePublicationWith(b1); pubLvl >= AreAlmostTheSame {
-				return AreTheSame, fmt.Sprintf("Titles are %s, Authors are %s, Publication are %s", lvl, authLvl, pubLvl)
-			}
-			return AreAlmostTheSame, fmt.Sprintf("Titles are %s, Authors are %s", lvl, authLvl)
-		}
-		return AreMaybeTheSame, fmt.Sprintf("Titles are %s", lvl)
-	}
-
-	return lvl, fmt.Sprintf("Titles are %s", lvl)
-}
-
-func (b Book) compareTitlesWith(b1 *Book) SimilarityLevel {
-	t, t1 := b.Title, b1.Title
-
-	if b.SubTitle != "" {
-		t += " " + b.SubTitle
-	}
-	if b1.SubTitle != "" {
-		t1 += " " + b1.SubTitle
-	}
-
-	if t == "" {
-		t = b.SeriesTitle
-	}
-	if t1 == "" {
-		t1 = b1.SeriesTitle
-	}
-
-	return compareNormalizedStrings(t, t1)
-}
-
+// compareAuthorsWith compares Book's Authors using the canonical identity of
+// each author as defined in the authority file, so that aliases or pseudonyms
+// of the same person are considered identical.
 func (b Book) compareAuthorsWith(b1 *Book) SimilarityLevel {
-	return compareLists(b.Authors, b1.Authors)
+	return compareLists(authorsIdentity(b.Authors), authorsIdentity(b1.Authors))
 }
 
-func (b Book) comparePublicationWith(b1 *Book) SimilarityLevel {
-	lvl := b.comparePublisherWith(b1)
-	if lvl >= AreAlmostTheSame {
-		if b.comparePublishedDateWith(b1) >= AreAlmostTheSame {
-			return AreTheSame
-		}
-		return AreAlmostTheSame
+func authorsIdentity(authors []string) []string {
+	if len(canonicalNames) == 0 && len(realNames) == 0 {
+		return authors
 	}
 
-	return lvl
+	identities := make([]string, len(authors))
+	for i, author := range authors {
+		identities[i] = nameIdentity(author)
+	}
+	return identities
 }
 
 func (b Book) comparePublisherWith(b1 *Book) SimilarityLevel {
@@ -181,40 +157,168 @@ func (b Book) compareSubjectWith(b1 *Book) SimilarityLevel {
 
 // compareStrings compares two strings considering their Jaro-Winkler distance
 func compareStrings(s1, s2 string) SimilarityLevel {
-	if s1 == "" || s2 == "" {
+	dist, ok := stringsSimilarity(s1, s2)
+	if !ok {
 		return AreNotComparable
 	}
+	return DefaultComparator.Thresholds.Level(dist)
+}
 
-	dist, err := edlib.StringsSimilarity(s1, s2, edlib.JaroWinkler)
-	if err != nil {
-		return AreNotComparable
+// stringsSimilarity computes the Jaro-Winkler similarity of two strings. It
+// returns false if strings are not comparable.
+func stringsSimilarity(s1, s2 string) (float64, bool) {
+	if s1 == "" || s2 == "" {
+		return 0, false
 	}
 
-	switch {
-	case dist > isAreTheSameThreshold:
-		return AreTheSame
-	case dist > isAreAlmostTheSameThreshold:
-		return AreAlmostTheSame
-	case dist > isAreMaybeTheSameThreshold:
-		return AreMaybeTheSame
+	dist, err := edlib.StringsSimilarity(s1, s2, edlib.JaroWinkler)
+	if err != nil {
+		return 0, false
 	}
 
-	return AreNotTheSame
+	return float64(dist), true
 }
 
 func compareNormalizedStrings(s1, s2 string) SimilarityLevel {
-	ns1, ns2 := normalizeString(s1), normalizeString(s2)
+	ns1, ns2 := DefaultComparator.normalize(s1), DefaultComparator.normalize(s2)
 	return compareStrings(ns1, ns2)
 }
 
-// compareLists compare two lists of strings, without considering order.
+// compareLists compares two lists of strings, without considering order.
+// Elements of both lists are paired with their most similar counterpart and
+// lists' similarity is estimated as the ratio of paired elements' similarity
+// to the number of distinct elements (Jaccard-like index). Lists whose
+// elements are all found in the other list (like an additional co-author)
+// are considered almost the same.
 func compareLists(l1, l2 []string) SimilarityLevel {
-	// TODO: improve heuristic to differentiate completely different lists from
-	// lists that are close like toto vs tata, toto -> AreAlmostTheSame
-	sorted1, sorted2 := append([]string{}, l1...), append([]string{}, l2...)
-	sort.Strings(sorted1)
-	sort.Strings(sorted2)
-	return compareStrings(fmt.Sprint(sorted1), fmt.Sprint(sorted2))
+	jaccard, coverage, ok := listsSimilarity(DefaultComparator.normalizeList(l1), DefaultComparator.normalizeList(l2), DefaultComparator.Thresholds)
+	if !ok {
+		return AreNotComparable
+	}
+	return DefaultComparator.Thresholds.listLevel(jaccard, coverage)
+}
+
+// listsSimilarity computes the Jaccard-like index of two lists of normalized
+// strings and the coverage of the shortest list by the other one. It returns
+// false if lists are not comparable.
+func listsSimilarity(l1, l2 [][]string, t Thresholds) (jaccard float64, coverage float64, ok bool) {
+	if len(l1) == 0 || len(l2) == 0 {
+		return 0, 0, false
+	}
+
+	score, matches := matchLists(l1, l2, t)
+
+	jaccard = score / float64(len(l1)+len(l2)-matches)
+	coverage = score / math.Min(float64(len(l1)), float64(len(l2)))
+	return jaccard, coverage, true
+}
+
+// matchLists pairs elements of two lists of normalized strings, most similar
+// elements first. Only elements that are at least maybe the same according to
+// Thresholds are paired.
+// It returns the sum of paired elements' similarity and the number of pairs.
+func matchLists(l1, l2 [][]string, t Thresholds) (float64, int) {
+	type pair struct {
+		i, j  int
+		score float64
+	}
+
+	var pairs []pair
+	for i := range l1 {
+		for j := range l2 {
+			if score := similarity(l1[i], l2[j], t); score > t.MaybeSame {
+				pairs = append(pairs, pair{i, j, score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(a, b int) bool { return pairs[a].score > pairs[b].score })
+
+	var score float64
+	var matches int
+	matched1, matched2 := make([]bool, len(l1)), make([]bool, len(l2))
+	for _, p := range pairs {
+		if matched1[p.i] || matched2[p.j] {
+			continue
+		}
+		matched1[p.i], matched2[p.j] = true, true
+		score, matches = score+p.score, matches+1
+	}
+
+	return score, matches
+}
+
+// similarity estimates the similarity of two normalized strings split into
+// words. Words made of a single letter are considered as initials of the
+// corresponding words of the other string (like "w m miller" and "walter m
+// miller"), their similarity being halfway between identical and the same
+// according to Thresholds.
+func similarity(w1, w2 []string, t Thresholds) float64 {
+	s1, s2 := strings.Join(w1, " "), strings.Join(w2, " ")
+	if s1 == s2 {
+		return 1
+	}
+
+	if matchInitials(w1, w2) {
+		return (1 + t.Same) / 2
+	}
+
+	dist, err := edlib.StringsSimilarity(s1, s2, edlib.JaroWinkler)
+	if err != nil {
+		return 0
+	}
+	return float64(dist)
+}
+
+// matchInitials checks whether two lists of words are identical, words made
+// of a single letter being allowed to match any word starting with this
+// letter. Last words (usually surnames) shall be identical and the shortest
+// list's first words shall match the other's first words.
+func matchInitials(w1, w2 []string) bool {
+	if len(w1) < 2 || len(w2) < 2 || w1[len(w1)-1] != w2[len(w2)-1] {
+		return false
+	}
+
+	var hasInitial bool
+	for i := 0; i < len(w1)-1 && i < len(w2)-1; i++ {
+		a, b := w1[i], w2[i]
+		switch {
+		case a == b:
+		case utf8.RuneCountInString(a) == 1 && strings.HasPrefix(b, a),
+			utf8.RuneCountInString(b) == 1 && strings.HasPrefix(a, b):
+			hasInitial = true
+		default:
+			return false
+		}
+	}
+
+	return hasInitial
+}
+
+// groupVariants groups strings that are variants of each other according to
+// areVariants. Each string is compared to the first string of each group, in
+// the supplied order. Only groups of at least two strings are returned.
+func groupVariants(list []string, areVariants func(s1, s2 string) bool) [][]string {
+	var groups [][]string
+
+	grouped := make([]bool, len(list))
+	for i, s := range list {
+		if grouped[i] {
+			continue
+		}
+
+		group := []string{s}
+		for j := i + 1; j < len(list); j++ {
+			if !grouped[j] && areVariants(s, list[j]) {
+				group, grouped[j] = append(group, list[j]), true
+			}
+		}
+
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
 }
 
 // compareNormalizedISBN compares two already 'normalized' ISBN.
@@ -256,14 +360,16 @@ func compareNormalizedDates(date1, date2 string) SimilarityLevel {
 	return AreNotTheSame
 }
 
-// normalizeString outputs a normalized version of input strings to ease
-// further fuzzy comparison. It notably uses case-folding and retains only
-// meaningful symbol (ie: removes punctuations).
-func normalizeString(s string) string {
-	// TODO: use golang.org/x/text/language to improve case-folding
-
+// NormalizeString outputs a normalized version of input strings to ease
+// further fuzzy comparison or searching. It notably uses case-folding, removes
+// accents and retains only meaningful symbol (ie: removes punctuations).
+//
+// Case-folding is locale-insensitive, letters whose case-folding depends on
+// the locale are folded so that their variants match whatever the locale (like
+// Turkish dotless 'ı' and dotted 'i', or German 'ß' and 'ss').
+func NormalizeString(s string) string {
 	t := transform.Chain(norm.NFD, cases.Fold(), runes.Remove(runes.In(unicode.Mn)), norm.NFC)
 	ns, _, _ := transform.String(t, s)
 
-	return reMeaningless.ReplaceAllString(ns, " ")
+	return reMeaningless.ReplaceAllString(foldReplacer.Replace(ns), " ")
 }
@@ -36,11 +36,47 @@ func TestNormalizeString(t *testing.T) {
 		{"你好", "你好"},
 		{"I'm born in 1976", "i m born in 1976"},
 		{"Je suis très honnoré de vous rencontrer", "je suis tres honnore de vous rencontrer"},
+		{"Straße", "strasse"},
+		{"STRASSE", "strasse"},
+		{"İstanbul", "istanbul"},
+		{"ıstanbul", "istanbul"},
+		{"ISTANBUL", "istanbul"},
+		{"Œuvres", "oeuvres"},
 	}
 
 	for _, tc := range testCases {
-		if got := normalizeString(tc.in); got != tc.want {
+		if got := NormalizeString(tc.in); got != tc.want {
 			t.Errorf("Fail to normalize '%s'.\nWant: %v\nGot : %v", tc.in, tc.want, got)
 		}
 	}
 }
+
+func TestCompareLists(t *testing.T) {
+	testCases := []struct {
+		in1, in2 []string
+		want     SimilarityLevel
+	}{
+		{[]string{"Jules Verne"}, []string{"Jules Verne"}, AreTheSame},
+		{[]string{"Jules Verne"}, []string{"jules  VERNE"}, AreTheSame},
+		{[]string{"Walter M. Miller"}, []string{"W. M. Miller"}, AreTheSame},
+		{[]string{"J. R. R. Tolkien"}, []string{"John Ronald Reuel Tolkien"}, AreTheSame},
+		{[]string{"Margaret Weis", "Tracy Hickman"}, []string{"Tracy Hickman", "Margaret Weis"}, AreTheSame},
+		{[]string{"Margaret Weis", "Tracy Hickman"}, []string{"M. Weis", "T. Hickman"}, AreTheSame},
+		{[]string{"Herodotus"}, []string{"Herodotus", "G. C. Macaulay"}, AreAlmostTheSame},
+		{[]string{"Charles de Secondat Montesquieu"}, []string{"Charles de Secondat, baron de Montesquieu"}, AreTheSame},
+		{[]string{"Fiction", "Science Fiction", "Adventure"}, []string{"Science Fiction", "Adventure stories"}, AreAlmostTheSame},
+		{[]string{"Fiction", "Science Fiction", "Adventure", "Voyages", "Space", "Robots"}, []string{"Fiction", "Science Fiction", "Adventure", "Voyages", "Space", "Aliens"}, AreMaybeTheSame},
+		{[]string{"Fiction", "Science Fiction", "Adventure"}, []string{"Science Fiction", "Travel"}, AreNotTheSame},
+		{[]string{"Jules Verne"}, []string{"Lewis Carroll"}, AreNotTheSame},
+		{[]string{"Beatrix Potter", "Jules Verne"}, []string{"Lewis Carroll", "Laozi"}, AreNotTheSame},
+		{[]string{"W. Miller"}, []string{"Henry Miller"}, AreNotTheSame},
+		{[]string{"Jules Verne"}, nil, AreNotComparable},
+		{[]string{""}, []string{"Jules Verne"}, AreNotComparable},
+	}
+
+	for _, tc := range testCases {
+		if got := compareLists(tc.in1, tc.in2); got != tc.want {
+			t.Errorf("Fail to compare lists %#v vs. %#v.\nWant: %v\nGot : %v", tc.in1, tc.in2, tc.want, got)
+		}
+	}
+}
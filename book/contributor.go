@@ -0,0 +1,148 @@
+package book
+
+import (
+	"sort"
+	"strings"
+)
+
+// List of the most common roles of Book's contributors expressed as MARC
+// relator codes (https://www.loc.gov/marc/relators/).
+const (
+	// RoleAuthor is the role of Book's authors.
+	RoleAuthor = "aut"
+	// RoleTranslator is the role of Book's translators.
+	RoleTranslator = "trl"
+	// RoleIllustrator is the role of Book's illustrators.
+	RoleIllustrator = "ill"
+	// RoleEditor is the role of Book's editors.
+	RoleEditor = "edt"
+	// RoleContributor is the role of contributors whose contribution is not
+	// specified.
+	RoleContributor = "ctb"
+)
+
+var (
+	// roleNames maps usual roles' names to their MARC relator code.
+	roleNames = map[string]string{
+		"author": RoleAuthor, "auteur": RoleAuthor, "writer": RoleAuthor, "creator": RoleAuthor,
+		"translator": RoleTranslator, "traducteur": RoleTranslator, "traductrice": RoleTranslator,
+		"illustrator": RoleIllustrator, "illustrateur": RoleIllustrator, "illustratrice": RoleIllustrator,
+		"editor": RoleEditor, "éditeur": RoleEditor, "editrice": RoleEditor, "éditrice": RoleEditor,
+		"contributor": RoleContributor,
+	}
+)
+
+// NormalizeRole returns the MARC relator code corresponding to a
+// contributor's role. role can be a relator code or a usual role name like
+// "translator".
+// If role is not known, it is returned lower-cased as-is.
+func NormalizeRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	role = strings.TrimPrefix(role, "marc:")
+
+	if code, known := roleNames[role]; known {
+		return code
+	}
+	return role
+}
+
+// AddContributor adds a contributor to the Book according to its role.
+// Authors and translators are respectively added to Book's Authors and
+// Translators, other contributors are recorded in Book's Contributors.
+// An empty role is considered as RoleContributor.
+func (b *Book) AddContributor(role string, name string) {
+	b.addContributor(role, name, "")
+}
+
+// addContributor adds a contributor to the Book like AddContributor does,
+// recording its sortable name if known. The sortable name is recorded before
+// the contributor's name is replaced by its canonical spelling so that it is
+// forgotten together with the replaced alias.
+func (b *Book) addContributor(role string, name string, fileAs string) {
+	name = b.cleanName(strings.TrimSpace(name))
+	if name == "" {
+		return
+	}
+	b.SetFileAs(name, strings.TrimSpace(fileAs))
+
+	switch role = NormalizeRole(role); role {
+	case RoleAuthor:
+		b.Authors = appendIfMissing(b.Authors, b.canonicalName(name))
+
+	case RoleTranslator:
+		b.Translators = appendIfMissing(b.Translators, name)
+
+	case "":
+		role = RoleContributor
+		fallthrough
+
+	default:
+		if b.Contributors == nil {
+			b.Contributors = make(map[string][]string)
+		}
+		b.Contributors[role] = appendIfMissing(b.Contributors[role], name)
+	}
+}
+
+// Role returns the role of a Book's contributor or an empty string if name
+// is not a known contributor.
+func (b Book) Role(name string) string {
+	switch {
+	case isInNames(name, b.Authors):
+		return RoleAuthor
+
+	case isInNames(name, b.Translators):
+		return RoleTranslator
+	}
+
+	roles := make([]string, 0, len(b.Contributors))
+	for role := range b.Contributors {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	for _, role := range roles {
+		if isInNames(name, b.Contributors[role]) {
+			return role
+		}
+	}
+
+	return ""
+}
+
+// assignRolesFrom moves Book's Authors that are known by ref with a different
+// role to the relevant contributor's list. It is typically used for
+// information sources (like Googlebooks) that do not distinguish
+// contributors' roles.
+func (b *Book) assignRolesFrom(ref *Book) {
+	authors := b.Authors[:0:0]
+	for _, author := range b.Authors {
+		if role := ref.Role(author); role != "" && role != RoleAuthor {
+			Debug.Printf("consider %s as %s instead of author", author, role)
+			b.AddContributor(role, author)
+			continue
+		}
+		authors = append(authors, author)
+	}
+	b.Authors = authors
+}
+
+// appendIfMissing appends name to names if not already present.
+func appendIfMissing(names []string, name string) []string {
+	if isInNames(name, names) {
+		return names
+	}
+	return append(names, name)
+}
+
+// isInNames checks whether a name is found in a list of names. Names are
+// compared after being normalized.
+func isInNames(name string, names []string) bool {
+	nname := strings.TrimSpace(NormalizeString(name))
+	for _, n := range names {
+		if strings.TrimSpace(NormalizeString(n)) == nname {
+			return true
+		}
+	}
+	return false
+}
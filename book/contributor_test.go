@@ -0,0 +1,74 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/pirmd/verify"
+)
+
+func TestNormalizeRole(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+	}{
+		{"aut", RoleAuthor},
+		{"TRL", RoleTranslator},
+		{"marc:ill", RoleIllustrator},
+		{"Editor", RoleEditor},
+		{"traducteur", RoleTranslator},
+		{"nrt", "nrt"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := NormalizeRole(tc.in); got != tc.out {
+			t.Errorf("Normalize role %#v failed.\nWant: %v\nGot : %v", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestAddContributor(t *testing.T) {
+	got := New()
+	got.AddContributor("aut", "Jules Verne")
+	got.AddContributor("", "Pierre-Jules Hetzel")
+	got.AddContributor("ill", "Edouard Riou")
+	got.AddContributor("illustrator", "Alphonse de Neuville")
+	got.AddContributor("trl", "VERNE, Michel")
+	got.AddContributor("aut", "Jules VERNE")
+
+	want := &Book{
+		Authors:     []string{"Jules Verne"},
+		Translators: []string{"Michel Verne"},
+		Contributors: map[string][]string{
+			RoleContributor: {"Pierre-Jules Hetzel"},
+			RoleIllustrator: {"Edouard Riou", "Alphonse de Neuville"},
+		},
+		FileAs: map[string]string{"Michel Verne": "Verne, Michel"},
+		Report: NewReport(),
+	}
+
+	if failure := verify.Equal(want, got); failure != nil {
+		t.Errorf("Adding contributors failed:\n%v", failure)
+	}
+}
+
+func TestAssignRolesFrom(t *testing.T) {
+	ref := &Book{
+		Authors:      []string{"Beatrix Potter"},
+		Translators:  []string{"Victorine Ballon"},
+		Contributors: map[string][]string{RoleIllustrator: {"Edouard Riou"}},
+	}
+
+	got := &Book{Authors: []string{"Beatrix Potter", "Victorine Ballon", "Edouard Riou"}}
+	got.assignRolesFrom(ref)
+
+	want := &Book{
+		Authors:      []string{"Beatrix Potter"},
+		Translators:  []string{"Victorine Ballon"},
+		Contributors: map[string][]string{RoleIllustrator: {"Edouard Riou"}},
+	}
+
+	if failure := verify.Equal(want, got); failure != nil {
+		t.Errorf("Assigning roles failed:\n%v", failure)
+	}
+}
@@ -0,0 +1,150 @@
+package book
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pirmd/epub"
+
+	"github.com/pirmd/libro/book/htmlutil"
+)
+
+var (
+	// ErrNoCover is raised if no cover can be found for a Book.
+	ErrNoCover = errors.New("no cover found")
+)
+
+// Cover returns the content of the Book's cover image and its media-type.
+// Cover is located using, by order of preference, EPUB3 manifest's
+// 'cover-image' property, EPUB2 'cover' meta or the first image of the first
+// spine item.
+// If no cover can be found, Cover returns ErrNoCover.
+func (b *Book) Cover() ([]byte, string, error) {
+	e, err := epub.Open(b.Path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer e.Close()
+
+	opf, err := e.Package()
+	if err != nil {
+		return nil, "", err
+	}
+
+	href, mediaType, err := findCover(e, opf)
+	if err != nil {
+		return nil, "", err
+	}
+	Debug.Printf("found cover at '%s' (%s)", href, mediaType)
+
+	r, err := e.OpenItem(href)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+
+	img, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return img, mediaType, nil
+}
+
+// findCover locates the cover of an EPUB and returns its href in the EPUB
+// archive and its media-type. EPUB2 'cover' meta that point to an unknown
+// manifest item are ignored (see unknownCoverItems).
+func findCover(e *epub.Epub, opf *epub.PackageDocument) (string, string, error) {
+	for _, item := range opf.Manifest.Items {
+		if isInProperties("cover-image", item.Properties) {
+			return item.Href, item.MediaType, nil
+		}
+	}
+
+	for _, m := range opf.Metadata.Meta {
+		if m.Name != "cover" || m.Content == "" {
+			continue
+		}
+
+		if item := getCoverMetaItem(opf, m.Content); item != nil {
+			return item.Href, item.MediaType, nil
+		}
+	}
+
+	if len(opf.Spine.Itemrefs) == 0 {
+		return "", "", ErrNoCover
+	}
+
+	first := getManifestItem(opf, opf.Spine.Itemrefs[0].IDref)
+	if first == nil || first.Href == "" || filepath.IsAbs(first.Href) {
+		return "", "", ErrNoCover
+	}
+
+	if strings.HasPrefix(first.MediaType, "image/") {
+		return first.Href, first.MediaType, nil
+	}
+
+	r, err := e.OpenItem(first.Href)
+	if err != nil {
+		return "", "", err
+	}
+	defer r.Close()
+
+	images, err := htmlutil.GetImagesFromHTML(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	if len(images) == 0 {
+		return "", "", ErrNoCover
+	}
+
+	href := path.Join(path.Dir(first.Href), images[0])
+	for _, item := range opf.Manifest.Items {
+		if item.Href == href {
+			return item.Href, item.MediaType, nil
+		}
+	}
+
+	return href, mime.TypeByExtension(path.Ext(href)), nil
+}
+
+// unknownCoverItems lists the manifest items that EPUB2 'cover' meta point
+// to but that do not exist.
+func unknownCoverItems(opf *epub.PackageDocument) []string {
+	var unknown []string
+	for _, m := range opf.Metadata.Meta {
+		if m.Name == "cover" && m.Content != "" && getCoverMetaItem(opf, m.Content) == nil {
+			unknown = append(unknown, m.Content)
+		}
+	}
+	return unknown
+}
+
+// getCoverMetaItem retrieves the manifest item an EPUB2 'cover' meta points
+// to, either by ID or by href. Returns nil if no item can be found.
+func getCoverMetaItem(opf *epub.PackageDocument, content string) *epub.Item {
+	for i, item := range opf.Manifest.Items {
+		if item.ID == content || item.Href == content {
+			return &opf.Manifest.Items[i]
+		}
+	}
+
+	return nil
+}
+
+// getManifestItem retrieves a manifest item from its ID. Returns nil if no
+// item can be found.
+func getManifestItem(opf *epub.PackageDocument, id string) *epub.Item {
+	for i, item := range opf.Manifest.Items {
+		if item.ID == id {
+			return &opf.Manifest.Items[i]
+		}
+	}
+
+	return nil
+}
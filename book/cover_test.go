@@ -0,0 +1,83 @@
+package book
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCover(t *testing.T) {
+	testCases := []struct {
+		in        string
+		mediaType string
+	}{
+		{"pg11.epub", "image/jpeg"},
+		{"pg24039.epub", "image/png"},
+		{"pg29052.epub", "image/jpeg"},
+	}
+
+	for _, tc := range testCases {
+		b := New()
+		b.Path = filepath.Join(testdataBooks, tc.in)
+
+		img, mediaType, err := b.Cover()
+		if err != nil {
+			t.Errorf("Fail to get cover of %s: %v", tc.in, err)
+			continue
+		}
+
+		if len(img) == 0 {
+			t.Errorf("Cover of %s is empty", tc.in)
+		}
+
+		if mediaType != tc.mediaType {
+			t.Errorf("Cover of %s has wrong media-type.\nWant: %s\nGot : %s", tc.in, tc.mediaType, mediaType)
+		}
+	}
+}
+
+func TestCoverWithUnknownMetaItem(t *testing.T) {
+	b := New()
+	b.Path = filepath.Join(t.TempDir(), "pg11.epub")
+
+	injection := map[string][2]string{"OEBPS/content.opf": {`<meta name="cover" content="item1"/>`, `<meta name="cover" content="missing"/>`}}
+	if err := injectInEpub(b.Path, filepath.Join(testdataBooks, "pg11.epub"), injection); err != nil {
+		t.Fatalf("Fail to prepare EPUB: %v", err)
+	}
+
+	if _, _, err := b.Cover(); err != nil {
+		t.Errorf("Fail to get cover of %s: %v", b.Path, err)
+	}
+
+	if b.HasIssue() {
+		t.Errorf("Getting cover should not modify Book's report: %v", b.Issues)
+	}
+
+	if err := b.CheckCover(); err != nil {
+		t.Fatalf("Fail to check cover of %s: %v", b.Path, err)
+	}
+
+	if len(b.Issues) != 1 {
+		t.Errorf("Cover meta pointing to an unknown item should be reported.\nWant: 1 issue\nGot : %v", b.Issues)
+	}
+}
+
+func TestCheckCoverWithSVG(t *testing.T) {
+	b := New()
+	b.Path = filepath.Join(t.TempDir(), "pg11.epub")
+
+	injection := map[string][2]string{
+		"OEBPS/content.opf": {`<item href="2075648043516833838_cover.jpg" id="item1" media-type="image/jpeg"/>`, `<item href="cover.svg" id="item1" media-type="image/svg+xml"/>`},
+		"OEBPS/cover.svg":   {"", `<?xml version="1.0" encoding="utf-8"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 600 800"><rect width="600" height="800"/></svg>`},
+	}
+	if err := injectInEpub(b.Path, filepath.Join(testdataBooks, "pg11.epub"), injection); err != nil {
+		t.Fatalf("Fail to prepare EPUB: %v", err)
+	}
+
+	if err := b.CheckCover(); err != nil {
+		t.Fatalf("Fail to check cover of %s: %v", b.Path, err)
+	}
+
+	if b.HasIssue() {
+		t.Errorf("SVG cover should not be reported: %v", b.Issues)
+	}
+}
@@ -1,11 +1,22 @@
 package book
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
 )
 
+// datePrecision is the precision of a time stamp.
+type datePrecision int
+
+const (
+	precisionYear datePrecision = iota
+	precisionMonth
+	precisionDay
+)
+
 // stampFormats lists all time formats that are recognized to parse a strings
 // representing a time stamp.
 var stampFormats = []string{
@@ -30,36 +41,169 @@ var stampFormats = []string{
 	"2006-01-02T15:04:05",
 }
 
-// ParseTimestamp parses a time stamp, trying different time format.
-func ParseTimestamp(stamp string) (t time.Time, err error) {
-	for _, fmt := range stampFormats {
-		if t, err = time.Parse(fmt, stamp); err == nil {
-			return
-		}
+var (
+	// monthNames maps the case-folded and accent-less names of months, or
+	// their usual abbreviation, in English, French, German, Spanish and
+	// Italian to their number.
+	monthNames = map[string]time.Month{
+		"january": 1, "jan": 1, "janvier": 1, "janv": 1, "januar": 1, "janner": 1, "enero": 1, "gennaio": 1,
+		"february": 2, "feb": 2, "fevrier": 2, "fevr": 2, "fev": 2, "februar": 2, "febrero": 2, "febbraio": 2,
+		"march": 3, "mar": 3, "mars": 3, "marz": 3, "marzo": 3,
+		"april": 4, "apr": 4, "avril": 4, "avr": 4, "abril": 4, "aprile": 4,
+		"may": 5, "mai": 5, "mayo": 5, "maggio": 5,
+		"june": 6, "jun": 6, "juin": 6, "juni": 6, "junio": 6, "giugno": 6,
+		"july": 7, "jul": 7, "juillet": 7, "juil": 7, "juli": 7, "julio": 7, "luglio": 7,
+		"august": 8, "aug": 8, "aout": 8, "agosto": 8,
+		"september": 9, "sep": 9, "sept": 9, "septembre": 9, "septiembre": 9, "setiembre": 9, "settembre": 9,
+		"october": 10, "oct": 10, "octobre": 10, "oktober": 10, "octubre": 10, "ottobre": 10,
+		"november": 11, "nov": 11, "novembre": 11, "noviembre": 11,
+		"december": 12, "dec": 12, "decembre": 12, "dezember": 12, "diciembre": 12, "dicembre": 12,
 	}
 
-	return
+	// seasonNames lists the case-folded and accent-less names of seasons in
+	// English, French, German, Spanish and Italian. Dates only defined by a
+	// season are known with a year precision.
+	seasonNames = []string{
+		"spring", "summer", "autumn", "fall", "winter",
+		"printemps", "ete", "automne", "hiver",
+		"fruhling", "fruhjahr", "sommer", "herbst",
+		"primavera", "verano", "otono", "invierno",
+		"estate", "autunno", "inverno",
+	}
+
+	// dateFillers lists the case-folded and accent-less words that are
+	// ignored when parsing a date (like articles, prepositions, week days or
+	// copyright notices).
+	dateFillers = []string{
+		"the", "of", "on", "in", "le", "la", "en", "de", "del", "di", "du", "el", "il", "der", "den", "am", "im",
+		"c", "ca", "circa", "cop", "copyright",
+		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+		"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche",
+		"montag", "dienstag", "mittwoch", "donnerstag", "freitag", "samstag", "sonnabend", "sonntag",
+		"lunes", "martes", "miercoles", "jueves", "viernes", "sabado", "domingo",
+		"lunedi", "martedi", "mercoledi", "giovedi", "venerdi", "sabato", "domenica",
+	}
+
+	// reDateYear captures a year, possibly prefixed by a copyright or circa
+	// indication (like "c1960").
+	reDateYear = regexp.MustCompile(`^(?:c|ca|cop)?(\d{4})$`)
+
+	// reDateDay captures a day of the month, possibly as an ordinal (like
+	// "1st", "1er" or "1º").
+	reDateDay = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th|er|re|e|eme|o|º|a|ª)?$`)
+)
+
+// ParseTimestamp parses a time stamp, trying different time format.
+// Besides numeric formats, ParseTimestamp understands dates written in
+// English, French, German, Spanish or Italian (like "March 1960", "1er
+// janvier 1999" or "Spring 1985") and copyright years (like "c1960").
+func ParseTimestamp(stamp string) (time.Time, error) {
+	t, _, err := parseTimestamp(stamp)
+	return t, err
 }
 
 // NormalizeDate standardizes time stamps format using 2006-01-02 notation.
 // If initial date is only a year, or only a year and a month, it does not
 // substitute day or month to 01.
 func NormalizeDate(stamp string) string {
-	t, err := ParseTimestamp(stamp)
+	t, precision, err := parseTimestamp(stamp)
 	if err != nil {
 		return stamp
 	}
 
-	switch len(cleanStamp(stamp)) {
-	case 4:
+	switch precision {
+	case precisionYear:
 		return t.Format("2006")
-	case 6:
+	case precisionMonth:
 		return t.Format("2006-01")
 	default:
 		return t.Format("2006-01-02")
 	}
 }
 
+// parseTimestamp parses a time stamp and guesses its precision.
+func parseTimestamp(stamp string) (t time.Time, precision datePrecision, err error) {
+	for _, fmt := range stampFormats {
+		if t, err = time.Parse(fmt, stamp); err == nil {
+			switch len(cleanStamp(stamp)) {
+			case 4:
+				return t, precisionYear, nil
+			case 6:
+				return t, precisionMonth, nil
+			default:
+				return t, precisionDay, nil
+			}
+		}
+	}
+
+	if t, precision, ok := parseNaturalDate(stamp); ok {
+		return t, precision, nil
+	}
+
+	return
+}
+
+// parseNaturalDate parses a date written in natural language, like "March
+// 12th, 1960", "1er janvier 1999", "12. März 1960", "1º de enero de 1999" or
+// "Spring 1985".
+func parseNaturalDate(stamp string) (time.Time, datePrecision, bool) {
+	var kinds string
+	var year, day int
+	var month time.Month
+
+	for _, w := range strings.Fields(NormalizeString(stamp)) {
+		if isInList(w, dateFillers) {
+			continue
+		}
+
+		if m := reDateYear.FindStringSubmatch(w); m != nil {
+			year, _ = strconv.Atoi(m[1])
+			kinds += "Y"
+			continue
+		}
+
+		if m := reDateDay.FindStringSubmatch(w); m != nil {
+			day, _ = strconv.Atoi(m[1])
+			kinds += "D"
+			continue
+		}
+
+		if w == "premier" || w == "primo" {
+			day, kinds = 1, kinds+"D"
+			continue
+		}
+
+		if m, isMonth := monthNames[w]; isMonth {
+			month, kinds = m, kinds+"M"
+			continue
+		}
+
+		if isInList(w, seasonNames) {
+			kinds += "S"
+			continue
+		}
+
+		return time.Time{}, 0, false
+	}
+
+	switch kinds {
+	case "Y", "SY":
+		return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), precisionYear, true
+
+	case "MY":
+		return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), precisionMonth, true
+
+	case "DMY", "MDY":
+		t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		if day < 1 || t.Day() != day {
+			return time.Time{}, 0, false
+		}
+		return t, precisionDay, true
+	}
+
+	return time.Time{}, 0, false
+}
+
 // Year get year information from a time stamps.
 // Returns empty string if stamp format can be recognized.
 func Year(stamp string) string {
@@ -20,6 +20,19 @@ func TestParseTimestamp(t *testing.T) {
 		{"17/01/1976", time.Date(1976, 1, 17, 0, 0, 0, 0, time.UTC)},
 		{"19760117", time.Date(1976, 1, 17, 0, 0, 0, 0, time.UTC)},
 		{"17011976", time.Date(1976, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"March 1960", time.Date(1960, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"mars 2012", time.Date(2012, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"1er janvier 1999", time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"Février 2001", time.Date(2001, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"March 12th, 1960", time.Date(1960, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"the 2nd of June 1953", time.Date(1953, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{"Montag, 12. März 1960", time.Date(1960, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"1º de enero de 1999", time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"15 settembre 1983", time.Date(1983, 9, 15, 0, 0, 0, 0, time.UTC)},
+		{"Spring 1985", time.Date(1985, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"c1960", time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"© 1960", time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"ca. 1960", time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)},
 	}
 
 	for _, tc := range testCases {
@@ -48,6 +61,13 @@ func TestNormalizeDate(t *testing.T) {
 		{"17/01/1976", "1976-01-17"},
 		{"19760117", "1976-01-17"},
 		{"17011976", "1976-01-17"},
+		{"March 1960", "1960-03"},
+		{"mars 2012", "2012-03"},
+		{"1er janvier 1999", "1999-01-01"},
+		{"Spring 1985", "1985"},
+		{"c1960", "1960"},
+		{"31 février 2001", "31 février 2001"},
+		{"sometime in 1960", "sometime in 1960"},
 	}
 
 	for _, tc := range testCases {
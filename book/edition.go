@@ -0,0 +1,140 @@
+package book
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// List of formats of a book's edition.
+const (
+	// FormatEbook is the format of electronic editions (like EPUB, PDF or
+	// Kindle).
+	FormatEbook = "ebook"
+	// FormatPrint is the format of printed editions (like paperback or
+	// hardcover).
+	FormatPrint = "print"
+	// FormatAudio is the format of audio book editions.
+	FormatAudio = "audio"
+)
+
+var (
+	// reISBNFormat is a regexp aiming at capturing the usual annotations
+	// that describe the format of the edition an ISBN is attributed to (like
+	// in "ISBN 978-... (epub)" or "ISBN papier : 978-...").
+	reISBNFormat = `(?i:(?:[ée]dition\s|version\s|livre\s)?(?:e-?book|epub|pdf|mobi|kindle|azw3?|num[ée]rique|[ée]lectronique|digital|broch[ée]e?|reli[ée]e?|papier|poche|imprim[ée]e?|print|paperback|hardcover|hardback|audio(?:book)?))`
+
+	// isbnFormats lists the formats of editions and the regexp that
+	// recognizes their usual annotations. Annotations are tried in order.
+	isbnFormats = []struct {
+		format string
+		re     *regexp.Regexp
+	}{
+		{FormatAudio, regexp.MustCompile(`(?i)audio`)},
+		{FormatEbook, regexp.MustCompile(`(?i)e-?book|epub|pdf|mobi|kindle|azw|num[ée]rique|[ée]lectronique|digital`)},
+		{FormatPrint, regexp.MustCompile(`(?i)broch[ée]|reli[ée]|papier|poche|imprim[ée]|print|paperback|hardcover|hardback`)},
+	}
+)
+
+// NormalizeISBNFormat returns the format of an edition (FormatEbook,
+// FormatPrint or FormatAudio) from its usual annotation (like "epub",
+// "broché" or "livre audio").
+// If the annotation is not recognized, an error will be raised.
+func NormalizeISBNFormat(annotation string) (string, error) {
+	for _, f := range isbnFormats {
+		if f.re.MatchString(annotation) {
+			return f.format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown edition format '%s'", annotation)
+}
+
+// SetISBNFormat records the format of the edition an ISBN of Book (its ISBN
+// or one of its AlternateISBN) is attributed to.
+// SetISBNFormat reports non-recognized ISBN or format but do not fail.
+func (b *Book) SetISBNFormat(isbn, format string) {
+	normISBN, err := NormalizeISBN(isbn)
+	if err != nil || normISBN == "" {
+		b.ReportIssue("non-supported ISBN (%s): %v", isbn, err)
+		return
+	}
+
+	normFormat, err := NormalizeISBNFormat(format)
+	if err != nil {
+		b.ReportWarning("unrecognized format (%s) of ISBN %s", format, isbn)
+		return
+	}
+
+	if b.ISBNFormat == nil {
+		b.ISBNFormat = make(map[string]string)
+	}
+	b.ISBNFormat[normISBN] = normFormat
+}
+
+// HasISBN checks whether an already 'normalized' ISBN is Book's ISBN or one
+// of its AlternateISBN.
+func (b Book) HasISBN(isbn string) bool {
+	return isbn != "" && (isbn == b.ISBN || isInList(isbn, b.AlternateISBN))
+}
+
+// addAlternateISBN records an ISBN of a related edition of Book, unless it is
+// already known.
+func (b *Book) addAlternateISBN(isbn string) {
+	if isbn == "" || b.HasISBN(isbn) {
+		return
+	}
+	b.AlternateISBN = append(b.AlternateISBN, isbn)
+}
+
+// areOtherEditions checks whether two ISBN are known to be attributed to
+// editions of different formats (like the print and ebook editions).
+func (b Book) areOtherEditions(isbn1, isbn2 string) bool {
+	f1, f2 := b.ISBNFormat[isbn1], b.ISBNFormat[isbn2]
+	return f1 != "" && f2 != "" && f1 != f2
+}
+
+// replaceISBN replaces Book's ISBN, Book's previous ISBN is kept as an
+// AlternateISBN.
+func (b *Book) replaceISBN(isbn string) {
+	previous := b.ISBN
+
+	b.ISBN = isbn
+	for i, alt := range b.AlternateISBN {
+		if alt == isbn {
+			b.AlternateISBN = append(b.AlternateISBN[:i], b.AlternateISBN[i+1:]...)
+			break
+		}
+	}
+
+	b.addAlternateISBN(previous)
+}
+
+// preferEbookISBN uses the ISBN of the ebook edition as Book's ISBN.
+func (b *Book) preferEbookISBN() {
+	if b.ISBNFormat[b.ISBN] == FormatEbook {
+		return
+	}
+
+	for _, isbn := range b.AlternateISBN {
+		if b.ISBNFormat[isbn] == FormatEbook {
+			Verbose.Printf("prefer ISBN of the ebook edition %v over %v", isbn, b.ISBN)
+			b.replaceISBN(isbn)
+			return
+		}
+	}
+}
+
+// hasExplainedISBN checks whether Book's ISBN is the one of its ebook edition
+// and each AlternateISBN is known to be attributed to an edition of another
+// format.
+func (b Book) hasExplainedISBN() bool {
+	if b.ISBNFormat[b.ISBN] != FormatEbook {
+		return false
+	}
+
+	for _, isbn := range b.AlternateISBN {
+		if !b.areOtherEditions(b.ISBN, isbn) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package book
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeISBNFormat(t *testing.T) {
+	testCases := []struct {
+		in    string
+		out   string
+		isErr bool
+	}{
+		{in: "epub", out: FormatEbook},
+		{in: "ePub", out: FormatEbook},
+		{in: "version numérique", out: FormatEbook},
+		{in: "e-book", out: FormatEbook},
+		{in: "broché", out: FormatPrint},
+		{in: "Relié", out: FormatPrint},
+		{in: "paperback", out: FormatPrint},
+		{in: "livre audio", out: FormatAudio},
+		{in: "audiobook", out: FormatAudio},
+		{in: "tome 1", isErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := NormalizeISBNFormat(tc.in)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("Normalizing format '%s' should fail", tc.in)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Fail to normalize format '%s': %v", tc.in, err)
+			continue
+		}
+
+		if got != tc.out {
+			t.Errorf("Normalizing format '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestISBNFormatGuessers(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out map[string]string
+	}{
+		{in: `ISBN 978-2-07-036822-8 (epub)`, out: map[string]string{"ISBN": "978-2-07-036822-8", "ISBNFormat": "epub"}},
+		{in: `ISBN : 978-2-7470-9059-9 (broché)`, out: map[string]string{"ISBN": "978-2-7470-9059-9", "ISBNFormat": "broché"}},
+		{in: `ISBN numérique : 978-2-07-036822-8`, out: map[string]string{"ISBN": "978-2-07-036822-8", "ISBNFormat": "numérique"}},
+		{in: `ISBN (livre audio) 978-2-07-036822-8`, out: map[string]string{"ISBN": "978-2-07-036822-8", "ISBNFormat": "livre audio"}},
+		{in: `ISBN 978-2-07-036822-8`, out: nil},
+	}
+
+	for _, tc := range testCases {
+		var got map[string]string
+		for _, re := range isbnFormatGuessers {
+			if got = reFindStringSubmatchAsMap(tc.in, re); got != nil {
+				break
+			}
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tc.out) {
+			t.Errorf("Guessing %#v failed:\nWant: %#v\nGot : %#v\n\n", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestMergeEditions(t *testing.T) {
+	b, err := NewFromMap(map[string]string{"ISBN": "978-2-7470-9059-9", "ISBNFormat": "broché"})
+	if err != nil {
+		t.Fatalf("Fail to create Book from map: %v", err)
+	}
+
+	if err := b.CompleteFromMap(map[string]string{"ISBN": "978-2-07-036822-8", "ISBNFormat": "epub"}); err != nil {
+		t.Fatalf("Fail to complete Book from map: %v", err)
+	}
+
+	if want := "9782070368228"; b.ISBN != want {
+		t.Errorf("Ebook's ISBN is not preferred.\nWant: %v\nGot : %v", want, b.ISBN)
+	}
+
+	if want := []string{"9782747090599"}; fmt.Sprint(b.AlternateISBN) != fmt.Sprint(want) {
+		t.Errorf("Print's ISBN is not kept as an alternate ISBN.\nWant: %v\nGot : %v", want, b.AlternateISBN)
+	}
+
+	if len(b.Warnings) > 0 {
+		t.Errorf("Merging ISBN of different editions should not raise warnings: %v", b.Warnings)
+	}
+
+	if !b.hasExplainedISBN() {
+		t.Errorf("ISBN of different editions should be explained.")
+	}
+
+	if err := b.CompleteFromMap(map[string]string{"ISBN": "978-0-596-52068-7"}); err != nil {
+		t.Fatalf("Fail to complete Book from map: %v", err)
+	}
+
+	if b.hasExplainedISBN() {
+		t.Errorf("ISBN of an unknown edition should not be explained.")
+	}
+}
@@ -1,6 +1,8 @@
 package book
 
 import (
+	"archive/zip"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -13,6 +15,14 @@ func NewFromEpub(path string) (*Book, error) {
 	b := New()
 	b.Path = path
 
+	// epub package panics when path is not a valid zip archive, make sure it
+	// is before going further.
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open '%s': %v", path, err)
+	}
+	z.Close()
+
 	mdata, err := epub.GetMetadataFromFile(b.Path)
 	if err != nil {
 		return nil, err
@@ -26,11 +36,25 @@ func NewFromEpub(path string) (*Book, error) {
 		b.SubTitle = mdata.SubTitle[0]
 	}
 
-	authors := make([]string, len(mdata.Creator))
-	for i, a := range mdata.Creator {
-		authors[i] = a.FullName
+	// dc:creator without role are considered as authors whereas dc:contributor
+	// without role are considered as (unspecified) contributors.
+	// creators keeps the authors' names as found in EPUB's metadata, that is
+	// before being replaced by their canonical spelling.
+	var creators []string
+	for _, a := range mdata.Creator {
+		role := a.Role
+		if role == "" {
+			role = RoleAuthor
+		}
+		if NormalizeRole(role) == RoleAuthor {
+			creators = append(creators, cleanAuthorName(strings.TrimSpace(a.FullName)))
+		}
+		b.addContributor(role, a.FullName, a.FileAs)
+	}
+
+	for _, a := range mdata.Contributor {
+		b.addContributor(a.Role, a.FullName, a.FileAs)
 	}
-	b.SetAuthors(authors)
 
 	if len(mdata.Description) > 0 {
 		b.SetDescription(mdata.Description[0])
@@ -41,6 +65,17 @@ func NewFromEpub(path string) (*Book, error) {
 	isbn := getEpubISBN(mdata)
 	b.SetISBN(isbn)
 
+	for _, id := range mdata.Identifier {
+		scheme, value, known := parseIdentifier(id.Scheme, id.Value)
+		if !known {
+			Debug.Printf("found unknown identifier: %+v", id)
+			continue
+		}
+		if scheme != IdentifierISBN {
+			b.SetIdentifier(scheme, value)
+		}
+	}
+
 	if len(mdata.Publisher) > 0 {
 		b.Publisher = mdata.Publisher[0]
 	}
@@ -55,6 +90,13 @@ func NewFromEpub(path string) (*Book, error) {
 		Debug.Printf("no 'publication date' found in epub's metadata (%+v)", mdata.Date)
 	}
 
+	for _, d := range mdata.Date {
+		if d.Event == "original-publication" {
+			b.SetOriginalPublishedDate(d.Stamp)
+			break
+		}
+	}
+
 	if mdata.Series != "" {
 		b.Series = mdata.Series
 	}
@@ -71,11 +113,27 @@ func NewFromEpub(path string) (*Book, error) {
 		b.SetLanguage(mdata.Language[0])
 	}
 
+	for _, meta := range mdata.Meta {
+		if meta.Name == "calibre:author_sort" && meta.Content != "" {
+			// calibre:author_sort lists the sortable names of all authors.
+			// Sortable names of authors replaced by their canonical spelling
+			// are ignored.
+			if sortnames := reList.Split(meta.Content, -1); len(sortnames) == len(creators) {
+				for i, author := range creators {
+					if isInList(author, b.Authors) && b.FileAs[author] == "" {
+						b.SetFileAs(author, strings.TrimSpace(sortnames[i]))
+					}
+				}
+			}
+		}
+	}
+
 	// We extract remaining unused metadata for later improving libro tools.
 	for _, meta := range mdata.Meta {
 		if meta.Name != "" && meta.Content != "" {
 			if meta.Name != "calibre:series" && meta.Name != "calibre:series_index" &&
 				meta.Name != "calibre:title_sort" && meta.Name != "calibre:timestamp" &&
+				meta.Name != "calibre:author_sort" &&
 				meta.Name != "cover" {
 				Debug.Printf("found 'Meta' unused information: %+v", meta)
 			}
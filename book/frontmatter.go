@@ -0,0 +1,238 @@
+package book
+
+import (
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"github.com/pirmd/epub"
+
+	"github.com/pirmd/libro/book/htmlutil"
+)
+
+const (
+	// frontMatterMaxItems is the number of spine items at the beginning of a
+	// Book that are considered as front-matter.
+	frontMatterMaxItems = 5
+
+	// frontMatterMaxSize is the amount of text (in bytes) of each
+	// front-matter item that is parsed to find Book's information. Title and
+	// copyright pages are short, limiting the amount of parsed text avoids
+	// matching the Book's actual content.
+	frontMatterMaxSize = 3000
+
+	// reName is a regexp aiming at capturing a 'reasonable' person or
+	// organisation name.
+	reName = `[\p{L}\p{N}][^\n,;()©:]{1,60}?`
+)
+
+var (
+	// reFrontMatterName is a regexp that identifies front-matter (or
+	// back-matter) items from their filename.
+	reFrontMatterName = regexp.MustCompile(`(?i)(?:copyright|colophon|title|titre|credit|mention|legal|imprint|front|info)`)
+
+	// frontMatterGuessers is a collection of regexp to extract information
+	// from Book's title or copyright pages. Unlike other guessers, every
+	// guesser is run and only the first found value of each attribute is
+	// retained, so that guessers are to be defined from the more reliable to
+	// the less reliable capture logic.
+	frontMatterGuessers = []*regexp.Regexp{
+		// Published by <Publisher>
+		regexp.MustCompile(`(?i)(?:published by|publié par|édité par|publicado por|pubblicato da|verlegt bei)\s+(?P<Publisher>` + reName + `)\s*(?:[\n,;(]|$)`),
+		// © <year>, Éditions <Publisher>
+		regexp.MustCompile(`(?i)(?:©|\(c\)|copyright)\s*(?:©\s*)?(?:\d{4}\s*,?\s*)?(?P<Publisher>(?:[ée]ditions|librairie|presses|verlag|editorial|edizioni)\s` + reName + `)\s*(?:[\n,;(]|$)`),
+		// © <year> by <Publisher> Books
+		regexp.MustCompile(`(?i)(?:©|\(c\)|copyright)\s*(?:©\s*)?(?:\d{4}\s*,?\s*)?(?:by\s+)?(?P<Publisher>` + reName + `\s(?:publishing|publishers|books|press|verlag))\b`),
+
+		// Dépôt légal : <month> <year>
+		// Copyright years are only used when no legal deposit notice is
+		// found (see guessDatesFromCopyrights) as they are the year of the
+		// work's or of its translation's first publication, not of this
+		// edition.
+		regexp.MustCompile(`(?i)d[ée]p[ôo]t\s+l[ée]gal\s*:?\s*(?:\p{L}+\.?\s+)?(?:\d{1,2}(?:er)?\s+)?(?:\p{L}+\.?\s+)?(?P<PublishedDate>\d{4})`),
+
+		// First published in <year>
+		regexp.MustCompile(`(?i)(?:originally published|first published|première publication|publié pour la première fois|paru pour la première fois|erstveröffentlichung|erstmals erschienen|erstausgabe|publicado originalmente|publicado por primera vez|pubblicato per la prima volta|prima pubblicazione)[^\n\d]{0,60}?(?P<OriginalPublishedDate>1\d{3}|20\d{2})\b`),
+
+		// Titre original : <OriginalTitle>
+		regexp.MustCompile(`(?im)(?:titre original|original title|originaltitel|título original|titolo originale)\s*:?\s*(?P<OriginalTitle>[^\n]+?)\s*$`),
+
+		// Traduit de l'anglais par <Translators>
+		regexp.MustCompile(`(?i)(?:traduit|traduction|adapté)\s+(?:de\s+l['’]|de\s+|du\s+)?(?:\p{L}+\s+)?(?:\([^)]*\)\s+)?par\s+(?P<Translators>` + reName + `)\s*(?:[\n,;(]|$)`),
+		// Translated from the English by <Translators>
+		regexp.MustCompile(`(?i)translated(?:\s+(?:from|into)\s+(?:the\s+)?\p{L}+)*\s+by\s+(?P<Translators>` + reName + `)\s*(?:[\n,;(]|$)`),
+		// Übersetzt von <Translators>, traducido por <Translators>
+		regexp.MustCompile(`(?i)(?:übersetzt von|aus dem \p{L}+ von|traducido por|traducción de|tradotto da|traduzione di)\s+(?P<Translators>` + reName + `)\s*(?:[\n,;(]|$)`),
+		// Traduction de <Translators>, as long as it does not introduce the
+		// original language (like "traduction de russe et présentation par")
+		regexp.MustCompile(`(?i)(?:traduit|traduction|adapté)\s+de\s+(?P<Translators>\p{L}+\s` + reName + `)\s*(?:[\n,;(]|$)`),
+
+		// Traduit de l'anglais, traducido del inglés, tradotto dall'inglese
+		regexp.MustCompile(`(?i)(?:traduit|traduction|adapté)\s+(?:de\s+l['’]|de\s+|du\s+)(?P<OriginalLanguage>\p{L}+)`),
+		regexp.MustCompile(`(?i)(?:traducido|traducida|traducción)\s+del?\s+(?P<OriginalLanguage>\p{L}+)`),
+		regexp.MustCompile(`(?i)(?:tradott[oa]|traduzione)\s+dal(?:l['’]\s*|lo\s+|\s+)(?P<OriginalLanguage>\p{L}+)`),
+		// Translated from the Russian
+		regexp.MustCompile(`(?i)translated\s+from\s+(?:the\s+)?(?P<OriginalLanguage>\p{L}+)`),
+		// Aus dem Englischen
+		regexp.MustCompile(`(?i)aus\s+dem\s+(?P<OriginalLanguage>\p{L}+?)(?:en)?(?:\P{L}|$)`),
+
+		// Nouvelle édition revue et augmentée, 2nd edition
+		regexp.MustCompile(`(?i)\b(?P<Edition>(?:\d+(?:st|nd|rd|th|e|re|ère|ème)|first|second|third|fourth|fifth|revised|new|updated|première|deuxième|troisième|nouvelle)\s+(?:[ée]dition)(?:\s+(?:revue|augmentée|corrigée|revised|updated|(?:and|et)))*)`),
+	}
+)
+
+var (
+	// reCopyrightYear captures the years of copyright notices.
+	reCopyrightYear = regexp.MustCompile(`(?i)(?:©|\(c\)|copyright)[^\n\d]{0,60}?(1[5-9]\d{2}|20\d{2})\b`)
+
+	// reTranslationCopyright identifies copyright notices of a translation
+	// (like "© 2014, Éditions Grasset, pour la traduction française").
+	reTranslationCopyright = regexp.MustCompile(`(?i)tradu|translat|übersetz`)
+)
+
+// guessFromFrontMatter extracts Book's information from its title and
+// copyright pages.
+// It returns information as a map whose keys are the attributes' name.
+func guessFromFrontMatter(path string) (map[string]string, error) {
+	txt, err := getFrontMatter(path)
+	if err != nil {
+		return nil, err
+	}
+
+	found := parseFrontMatter(txt)
+	if found == nil {
+		Debug.Printf("no information found in front-matter")
+		return nil, nil
+	}
+
+	Debug.Printf("found information in front-matter: '%+v'", found)
+	return found, nil
+}
+
+// parseFrontMatter extracts Book's information from the text of title and
+// copyright pages.
+func parseFrontMatter(txt string) map[string]string {
+	found := make(map[string]string)
+	for _, re := range frontMatterGuessers {
+		for attr, value := range reFindStringSubmatchAsMap(txt, re) {
+			if value = strings.Trim(value, " \t.,;:"); value == "" {
+				continue
+			}
+
+			if attr == "OriginalLanguage" {
+				// Translation notices also introduce translators' names (like
+				// "traduction de Jean Dupont" or "traduction de Ben Martin"
+				// whose first name looks like a language code): only
+				// language names are accepted.
+				if _, found := lookupLanguageName(value); !found {
+					continue
+				}
+			}
+
+			if attr == "Translators" {
+				// Conversely, translation notices introducing the original
+				// language are not about translators.
+				if _, found := lookupLanguageName(strings.Fields(value)[0]); found {
+					continue
+				}
+			}
+
+			if _, exists := found[attr]; !exists {
+				found[attr] = value
+			}
+		}
+	}
+
+	guessDatesFromCopyrights(txt, found)
+
+	if len(found) == 0 {
+		return nil
+	}
+	return found
+}
+
+// guessDatesFromCopyrights guesses publication years from copyright notices
+// when they are not found otherwise.
+//
+// PublishedDate is the year of the translation's copyright notice (like "©
+// 2014, Éditions Grasset, pour la traduction française") or else the latest
+// copyright year.
+//
+// OriginalPublishedDate of a translated work is the earliest year of the
+// copyright notices that are not about the translation (like "© 1959, Harper
+// Lee"). Nothing is guessed without an explicit translation's copyright
+// notice as several copyright years can also be those of a renewal or of a
+// reprint.
+func guessDatesFromCopyrights(txt string, found map[string]string) {
+	var translationYear, originalYear, latestYear string
+	for _, line := range strings.Split(txt, "\n") {
+		for _, m := range reCopyrightYear.FindAllStringSubmatch(line, -1) {
+			if m[1] > latestYear {
+				latestYear = m[1]
+			}
+
+			switch {
+			case reTranslationCopyright.MatchString(line):
+				if translationYear == "" || m[1] > translationYear {
+					translationYear = m[1]
+				}
+			case originalYear == "" || m[1] < originalYear:
+				originalYear = m[1]
+			}
+		}
+	}
+
+	if _, exists := found["PublishedDate"]; !exists {
+		switch {
+		case translationYear != "":
+			found["PublishedDate"] = translationYear
+		case latestYear != "":
+			found["PublishedDate"] = latestYear
+		}
+	}
+
+	if _, exists := found["OriginalPublishedDate"]; !exists {
+		if translationYear != "" && originalYear != "" && originalYear < translationYear {
+			found["OriginalPublishedDate"] = originalYear
+		}
+	}
+}
+
+// getFrontMatter retrieves the text of a Book's front-matter, that is the text
+// of the first spine items or of the spine items whose name suggests that they
+// are a title or a copyright page.
+func getFrontMatter(path string) (string, error) {
+	txt := new(strings.Builder)
+
+	var i int
+	if err := epub.WalkReadingContent(path, func(r io.Reader, fi fs.FileInfo) error {
+		i++
+		if i > frontMatterMaxItems && !reFrontMatterName.MatchString(fi.Name()) {
+			return nil
+		}
+
+		rawr, err := htmlutil.GetRawTextFromHTML(r)
+		if err != nil {
+			return err
+		}
+
+		raw, err := io.ReadAll(rawr)
+		if err != nil {
+			return err
+		}
+
+		item := trimBoilerplate(string(raw))
+		if len(item) > frontMatterMaxSize {
+			item = strings.ToValidUTF8(item[:frontMatterMaxSize], "")
+		}
+
+		txt.WriteString(item)
+		txt.WriteByte('\n')
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return txt.String(), nil
+}
@@ -0,0 +1,64 @@
+package book
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out map[string]string
+	}{
+		{
+			"Titre original : The Lord of the Rings\nTraduit de l'anglais par Francis Ledoux\n© Christian Bourgois éditeur, 1972\nDépôt légal : mars 2012",
+			map[string]string{"OriginalTitle": "The Lord of the Rings", "OriginalLanguage": "anglais", "Translators": "Francis Ledoux", "PublishedDate": "2012"},
+		},
+		{
+			"© Éditions Gallimard, 1960\nNouvelle édition revue et augmentée",
+			map[string]string{"Publisher": "Éditions Gallimard", "PublishedDate": "1960", "Edition": "Nouvelle édition revue et augmentée"},
+		},
+		{
+			"Copyright © 1954 by Penguin Books\nTranslated from the Russian by Richard Pevear & Larissa Volokhonsky\nSecond edition",
+			map[string]string{"Publisher": "Penguin Books", "OriginalLanguage": "Russian", "Translators": "Richard Pevear & Larissa Volokhonsky", "PublishedDate": "1954", "Edition": "Second edition"},
+		},
+		{
+			"© 1959, Harper Lee\n© 2014, Éditions Grasset, pour la traduction française\nTraduction de Isabelle Stoïanov",
+			map[string]string{"Publisher": "Éditions Grasset", "Translators": "Isabelle Stoïanov", "PublishedDate": "2014", "OriginalPublishedDate": "1959"},
+		},
+		{
+			"First published in Great Britain in 1937\nThis edition published 2012",
+			map[string]string{"OriginalPublishedDate": "1937"},
+		},
+		{
+			"Aus dem Französischen von Hans Meier",
+			map[string]string{"OriginalLanguage": "Französisch", "Translators": "Hans Meier"},
+		},
+		{
+			"TRADUIT PAR\nVICTORINE BALLON ET JULIENNE PROFICHET",
+			map[string]string{"Translators": "VICTORINE BALLON ET JULIENNE PROFICHET"},
+		},
+		{
+			"© 1987 Penguin Books\n© 2003 Penguin Books\nThis edition published 2012",
+			map[string]string{"Publisher": "Penguin Books", "PublishedDate": "2003"},
+		},
+		{
+			"Traduction de Ben Martin",
+			map[string]string{"Translators": "Ben Martin"},
+		},
+		{
+			"Traduction de russe et présentation par Jean Dupont",
+			map[string]string{"OriginalLanguage": "russe"},
+		},
+		{
+			"Chapter 1\nIt was a dark and stormy night.",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := parseFrontMatter(tc.in); fmt.Sprint(got) != fmt.Sprint(tc.out) {
+			t.Errorf("Parsing front-matter %#v failed:\nWant: %#v\nGot : %#v", tc.in, tc.out, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package book
 
 import (
+	"strings"
+
 	"github.com/pirmd/libro/book/googlebooks"
 )
 
@@ -16,6 +18,8 @@ func (b *Book) SearchOnGooglebooks(MaxResults int) ([]*Book, error) {
 	books := make([]*Book, len(found))
 	for i, vi := range found {
 		books[i] = newFromVolumeInfo(vi)
+		// Googlebooks does not distinguish contributors' roles.
+		books[i].assignRolesFrom(b)
 	}
 
 	return books, nil
@@ -49,6 +53,32 @@ func newFromVolumeInfo(vi *googlebooks.VolumeInfo) *Book {
 	b.Title = vi.Title
 	b.SetAuthors(vi.Authors)
 	b.SetISBN(getVolumeInfoISBN(vi))
+	for _, id := range vi.Identifier {
+		if strings.HasPrefix(id.Type, "ISBN") {
+			// ISBN_10 and ISBN_13 of the same edition are identical once
+			// normalized, others are related editions.
+			if isbn, err := NormalizeISBN(id.Identifier); err == nil {
+				b.addAlternateISBN(isbn)
+			}
+		}
+	}
+	// Googlebooks' industry identifiers do not tell the format of the
+	// edition they identify (saleInfo's isEbook only tells that an ebook is
+	// sold, not which ISBN identifies it), ISBNFormat is therefore not set.
+	if vi.ID != "" {
+		b.SetIdentifier(IdentifierGooglebooks, vi.ID)
+	}
+	for _, id := range vi.Identifier {
+		switch id.Type {
+		case "ISSN":
+			b.SetIdentifier(IdentifierISSN, id.Identifier)
+		case "OTHER":
+			// OTHER identifiers are formatted like "OCLC:12345678"
+			if scheme, value, known := parseIdentifier("", id.Identifier); known && scheme != IdentifierISBN {
+				b.SetIdentifier(scheme, value)
+			}
+		}
+	}
 	b.SubTitle = vi.SubTitle
 	b.Publisher = vi.Publisher
 	b.SetPublishedDate(vi.PublishedDate)
@@ -67,8 +97,6 @@ func getVolumeInfoISBN(vi *googlebooks.VolumeInfo) (isbn string) {
 		}
 
 		if len(isbn) == 13 {
-			// TODO: populate AlternateISBN with further possible ISBN found in
-			// vi.Identifier
 			break
 		}
 	}
@@ -67,6 +67,9 @@ func (api *API) SearchVolume(vi *VolumeInfo) ([]*VolumeInfo, error) {
 
 	var res []*VolumeInfo
 	for _, v := range vol.Items {
+		if v.VolumeInfo != nil {
+			v.VolumeInfo.ID = v.ID
+		}
 		res = append(res, v.VolumeInfo)
 	}
 
@@ -105,12 +108,13 @@ type volumes struct {
 }
 
 type volume struct {
+	ID         string      `json:"id"`
 	VolumeInfo *VolumeInfo `json:"volumeInfo"`
 }
 
 // Identifier represents an industry standard identifier.
 type Identifier struct {
-	// Type is the identifier type such as ISBN, ISBN_10, ISBN_13.
+	// Type is the identifier type such as ISBN_10, ISBN_13, ISSN or OTHER.
 	Type string `json:"type"`
 	// Identifier is the identifier value.
 	Identifier string `json:"identifier"`
@@ -118,6 +122,9 @@ type Identifier struct {
 
 // VolumeInfo gathers information obtained from GoogleBooks API
 type VolumeInfo struct {
+	// ID is the GoogleBooks' identifier of the volume.
+	ID string `json:"-"`
+
 	// Title is the volume's title.
 	Title string `json:"title"`
 
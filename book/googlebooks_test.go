@@ -40,3 +40,28 @@ func TestSearchOnGooglebooks(t *testing.T) {
 		t.Fatalf("Metadata is not as expected:\n%v", failure)
 	}
 }
+
+func TestSearchOnGooglebooksISBNFormat(t *testing.T) {
+	httpmock := verify.StartMockHTTPResponse(testdata)
+	defer httpmock.Stop()
+
+	Verbose, Debug = verify.NewLogger(t), verify.NewLogger(t)
+
+	b := New()
+	b.SetISBN("9782072477065")
+
+	// Googlebooks' answer is flagged as an ebook (saleInfo's isEbook) but its
+	// industry identifiers do not tell the format of their edition.
+	got, err := b.SearchOnGooglebooks(3)
+	if err != nil {
+		t.Fatalf("Fail to search (mocked) googlebooks: %v", err)
+	}
+
+	if len(got) != 1 || got[0].ISBN != "9782072477065" {
+		t.Fatalf("Searching ISBN on (mocked) googlebooks failed.\nWant: 9782072477065\nGot : %+v", got)
+	}
+
+	if len(got[0].ISBNFormat) != 0 {
+		t.Errorf("ISBN format should not be guessed from googlebooks' sale information.\nGot : %v", got[0].ISBNFormat)
+	}
+}
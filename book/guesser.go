@@ -1,13 +1,16 @@
 package book
 
 import (
+	"bytes"
 	"io"
 	"io/fs"
 	"regexp"
+	"strings"
 
 	"github.com/pirmd/epub"
 
 	"github.com/pirmd/libro/book/htmlutil"
+	"github.com/pirmd/libro/book/langdetect"
 )
 
 const (
@@ -24,6 +27,14 @@ const (
 
 	// reLang is a regexp aiming at capturing any 'reasonable' language identifiers.
 	reLang = `\s*\p{Ps}(?P<Language>[_-a-zA-Z]{2,5})\p{Pe}`
+
+	// langSampleSize is the amount of text (in bytes) extracted from the
+	// beginning of a Book's content to detect its language.
+	langSampleSize = 20000
+
+	// langMinConfidence is the confidence level above which a detected
+	// language is trusted.
+	langMinConfidence = 0.05
 )
 
 var (
@@ -69,11 +80,28 @@ var (
 		regexp.MustCompile(`^` + reSeriesIndex + `\s*[.,-]\s*(?P<SeriesTitle>.+)$`),
 	}
 
-	// contentGuesser is a regexp that extracts information from a Book's content.
-	// ISBN: <isbn> ou EAN: <isbn>
-	contentGuesser = regexp.MustCompile(
-		`(?:(?:ISBN)|(?:EAN)).*?\p{Zs}?:?\p{Zs}?` + reISBN,
-	)
+	// contentGuessers is a collection of regexp that extracts information
+	// from a Book's content.
+	contentGuessers = append([]*regexp.Regexp{
+		// ISBN: <isbn> ou EAN: <isbn>
+		regexp.MustCompile(`(?:(?:ISBN)|(?:EAN)).*?\p{Zs}?:?\p{Zs}?` + reISBN),
+	}, isbnFormatGuessers...)
+
+	// isbnFormatGuessers is a collection of regexp that extracts from a
+	// Book's content an ISBN together with the format of the edition it
+	// identifies.
+	isbnFormatGuessers = []*regexp.Regexp{
+		// ISBN <isbn> (<format>)
+		regexp.MustCompile(`(?:(?:ISBN)|(?:EAN)).*?\p{Zs}?:?\p{Zs}?` + reISBN + `\p{Zs}*\p{Ps}(?P<ISBNFormat>` + reISBNFormat + `)\p{Pe}`),
+		// ISBN <format>: <isbn>
+		regexp.MustCompile(`(?:(?:ISBN)|(?:EAN))\p{Zs}*\p{Ps}?(?P<ISBNFormat>` + reISBNFormat + `)\p{Pe}?\p{Zs}?:?\p{Zs}?` + reISBN),
+	}
+
+	// reGutenbergStart and reGutenbergEnd are regexps that capture the
+	// boundaries of the actual content of Project Gutenberg's books, that
+	// otherwise contain a (long) license in English.
+	reGutenbergStart = regexp.MustCompile(`(?i)\*\*\*\s*START OF (?:THE|THIS) PROJECT GUTENBERG EBOOK[^*]*\*\*\*`)
+	reGutenbergEnd   = regexp.MustCompile(`(?i)\*\*\*\s*END OF (?:THE|THIS) PROJECT GUTENBERG EBOOK`)
 
 	// titleCleaners is a collection of regexp that pre-processes bad-formatted Titles
 	titleCleaners = []*regexp.Regexp{
@@ -86,40 +114,131 @@ var (
 
 // NewFromFilename creates a Book whose information are guessed from its filename.
 func NewFromFilename(path string) (*Book, error) {
-	return guess(path, pathGuessers...)
+	return guess(path, withUserGuessers(TargetPath, pathGuessers...)...)
 }
 
 // NewFromContent creates a Book whose information are guessed from its Content.
+// Besides information found anywhere in the content (like ISBN), Book's
+// title and copyright pages are parsed to find Publisher, publication year
+// (from legal deposit notices or else from copyright notices),
+// OriginalTitle, OriginalPublishedDate, OriginalLanguage,
+// Translators and Edition.
 func NewFromContent(path string) (*Book, error) {
-	return grep(path, contentGuesser)
+	b, err := grep(path, withUserGuessers(TargetContent, contentGuessers...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := guessFromFrontMatter(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if found == nil {
+		return b, nil
+	}
+
+	if b == nil {
+		b = New()
+	}
+
+	if err := b.CompleteFromMap(found); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// GuessLanguageFromContent detects Book's language from the text of its
+// first spine items. If Book's Language is unknown, it is set to the detected
+// one, otherwise a warning is raised should declared and detected languages
+// disagree.
+func (b *Book) GuessLanguageFromContent() error {
+	sample := new(strings.Builder)
+	if err := epub.WalkReadingContent(b.Path, func(r io.Reader, fi fs.FileInfo) error {
+		rawr, err := htmlutil.GetRawTextFromHTML(r)
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.Copy(sample, rawr); err != nil {
+			return err
+		}
+		sample.WriteByte('\n')
+
+		if sample.Len() > langSampleSize {
+			return epub.ErrStopWalk
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	lang, confidence := langdetect.Detect(trimBoilerplate(sample.String()))
+	Debug.Printf("detected language from content is '%s' (confidence: %.2f)", lang, confidence)
+	if lang == "" || confidence < langMinConfidence {
+		return nil
+	}
+
+	switch {
+	case b.Language == "":
+		Verbose.Printf("set empty Language to %v", lang)
+		b.Language = lang
+
+	case !sameLanguage(b.Language, lang):
+		b.ReportWarning("book's Language (%s) differs from language detected from its content (%s).", b.Language, lang)
+	}
+
+	return nil
+}
+
+// trimBoilerplate removes from a text well-known boilerplate that is not part
+// of the actual book's content (like Project Gutenberg's license).
+func trimBoilerplate(txt string) string {
+	if loc := reGutenbergStart.FindStringIndex(txt); loc != nil {
+		txt = txt[loc[1]:]
+	}
+
+	if loc := reGutenbergEnd.FindStringIndex(txt); loc != nil {
+		txt = txt[:loc[0]]
+	}
+
+	return txt
 }
 
 // GuessFromMetadata tries to guess Book's information based on known
-// attributes (like Book's Title).
+// attributes (like Book's Title). Guessed Series is replaced by its canonical
+// name if known by the series' registry.
 func (b *Book) GuessFromMetadata() error {
 	if b.Title != "" {
 		Debug.Printf("guess Series from Title '%s'", b.Title)
-		if err := b.guess(b.Title, seriesGuessers...); err != nil {
+		if err := b.guess(b.Title, withUserGuessers(TargetTitle, seriesGuessers...)...); err != nil {
 			return err
 		}
 	}
 
 	if b.SubTitle != "" {
 		Debug.Printf("guess Series from Sub-Title '%s'", b.SubTitle)
-		if err := b.guess(b.SubTitle, seriesGuessers...); err != nil {
+		if err := b.guess(b.SubTitle, withUserGuessers(TargetSubTitle, seriesGuessers...)...); err != nil {
 			return err
 		}
 	}
 
+	b.setCanonicalSeries()
 	return nil
 }
 
 // CleanMetadata cleans Book's metadata.
 func (b *Book) CleanMetadata() error {
-	if err := b.clean(b.Title, titleCleaners...); err != nil {
+	if err := b.clean(b.Title, withUserCleaners(TargetTitle, titleCleaners...)...); err != nil {
 		return err
 	}
 
+	if b.SubTitle != "" {
+		if err := b.clean(b.SubTitle, withUserCleaners(TargetSubTitle)...); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -159,14 +278,15 @@ func guess(s string, guessers ...*regexp.Regexp) (*Book, error) {
 	return nil, nil
 }
 
-// grep extracts new Book's attributes from its content by applying a Regexp.
+// grep extracts new Book's attributes from its content by applying a list of
+// Regexp.
 // Regexp guesses new attribute's value using capturing group whose name shall
 // correspond to the attribute to create. Unknown attribute name will raise an
 // error.
 // Several matches for the same attribute can be returned, management of
 // inconsistent values is left to Book.CompleteFromMap logic, eventually
 // reporting to end-user such situation.
-func grep(path string, re *regexp.Regexp) (*Book, error) {
+func grep(path string, guessers ...*regexp.Regexp) (*Book, error) {
 	// TODO: I'm quite 'defensive' here as I capture every matches and report
 	// possible inconsistent values. This can maybe be removed later one once
 	// better confident in the heuristic so that we can just stop on the first
@@ -179,10 +299,17 @@ func grep(path string, re *regexp.Regexp) (*Book, error) {
 			return err
 		}
 
-		matches := reFindReaderSubmatchAsMap(rawr, re)
-		if matches != nil {
-			Debug.Printf("found information in %s: '%+v'", fi.Name(), matches)
-			found = append(found, matches...)
+		raw, err := io.ReadAll(rawr)
+		if err != nil {
+			return err
+		}
+
+		for _, re := range guessers {
+			matches := reFindReaderSubmatchAsMap(bytes.NewReader(raw), re)
+			if matches != nil {
+				Debug.Printf("found information in %s: '%+v'", fi.Name(), matches)
+				found = append(found, matches...)
+			}
 		}
 
 		return nil
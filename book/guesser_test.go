@@ -2,6 +2,7 @@ package book
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 )
 
@@ -106,7 +107,7 @@ func TestContentGuesser(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		got := reFindStringSubmatchAsMap(tc.in, contentGuesser)
+		got := reFindStringSubmatchAsMap(tc.in, contentGuessers[0])
 
 		if fmt.Sprint(got) != fmt.Sprint(tc.out) {
 			t.Errorf("Guessing %#v failed:\nWant: %#v\nGot : %#v\n\n", tc.in, tc.out, got)
@@ -171,3 +172,29 @@ func TestTitleCleaners(t *testing.T) {
 		}
 	}
 }
+
+func TestGuessLanguageFromContent(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"pg11.epub", "en"},
+		{"pg24039.epub", "zh"},
+		{"pg27573.epub", "fr"},
+		{"pg29052.epub", "fr"},
+	}
+
+	for _, tc := range testCases {
+		b := New()
+		b.Path = filepath.Join(testdataBooks, tc.in)
+
+		if err := b.GuessLanguageFromContent(); err != nil {
+			t.Errorf("Fail to guess language of %s: %v", tc.in, err)
+			continue
+		}
+
+		if b.Language != tc.want {
+			t.Errorf("Guess language of %s failed.\nWant: %s\nGot : %s", tc.in, tc.want, b.Language)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package htmlutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// headingLevel lists HTML headings with their level.
+var headingLevel = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// Accessibility represents the outcome of an accessibility inspection of
+// HTML content.
+type Accessibility struct {
+	// Issues lists messages describing encountered accessibility issues.
+	Issues []string
+
+	// Landmarks is set if a navigation landmarks (nav with
+	// epub:type=landmarks) has been found.
+	Landmarks bool
+
+	// PageList is set if a page-list (nav with epub:type=page-list) has been
+	// found.
+	PageList bool
+}
+
+// InspectAccessibility checks that io.Reader contains HTML that can be
+// accessed by visually impaired readers. It notably looks for images without
+// alternate text, documents without language declaration, skipped heading
+// levels and tables without headers.
+// As EPUB's content is usually split in several documents that do not start
+// with a top-level heading, heading levels are only checked from the first
+// heading of the document.
+//
+// Limitation: InspectAccessibility only relies on HTML tokenization and
+// follows a simple approach, it is far from replacing a proper accessibility
+// checker like Ace by DAISY.
+func InspectAccessibility(r io.Reader) (*Accessibility, error) {
+	a := &Accessibility{}
+	reportIssue := func(format string, args ...interface{}) {
+		a.Issues = append(a.Issues, fmt.Sprintf(format, args...))
+	}
+
+	var lastHeading int
+	// tableHasHeader tells, for each (nested) table being read, whether a
+	// header cell has been found.
+	var tableHasHeader []bool
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		if nextToken(tokenizer) == html.ErrorToken {
+			if err := tokenizer.Err(); err != nil {
+				if err == io.EOF {
+					return a, nil
+				}
+				return a, err
+			}
+		}
+
+		switch token := tokenizer.Token(); token.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.DataAtom {
+			case atom.Html:
+				if getAttr(token, "xml:lang") == "" && getAttr(token, "lang") == "" {
+					reportIssue("document has no language declaration (xml:lang or lang)")
+				}
+
+			case atom.Img:
+				if _, ok := lookupAttr(token, "alt"); !ok {
+					reportIssue("image '%s' has no alternate text", getAttr(token, "src"))
+				}
+
+			case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+				lvl := headingLevel[token.DataAtom]
+				if lastHeading > 0 && lvl > lastHeading+1 {
+					reportIssue("heading level skipped (%s follows h%d)", token.Data, lastHeading)
+				}
+				lastHeading = lvl
+
+			case atom.Table:
+				// A self-closing table has no end tag to pop it.
+				if token.Type == html.StartTagToken {
+					tableHasHeader = append(tableHasHeader, false)
+				}
+
+			case atom.Th:
+				if len(tableHasHeader) > 0 {
+					tableHasHeader[len(tableHasHeader)-1] = true
+				}
+
+			case atom.Nav:
+				for _, t := range strings.Fields(getAttr(token, "epub:type")) {
+					switch t {
+					case "landmarks":
+						a.Landmarks = true
+					case "page-list":
+						a.PageList = true
+					}
+				}
+			}
+
+		case html.EndTagToken:
+			if token.DataAtom == atom.Table && len(tableHasHeader) > 0 {
+				if !tableHasHeader[len(tableHasHeader)-1] {
+					reportIssue("table has no header cells (th)")
+				}
+				tableHasHeader = tableHasHeader[:len(tableHasHeader)-1]
+			}
+		}
+	}
+}
+
+// lookupAttr retrieves the value of a token's attribute and whether it
+// exists.
+func lookupAttr(token html.Token, key string) (string, bool) {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+
+	return "", false
+}
+
+// getAttr retrieves the value of a token's attribute. It returns an empty
+// string if attribute does not exist.
+func getAttr(token html.Token, key string) string {
+	v, _ := lookupAttr(token, key)
+	return v
+}
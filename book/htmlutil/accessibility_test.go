@@ -0,0 +1,51 @@
+package htmlutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInspectAccessibility(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want int
+	}{
+		{`<html lang="en"><body><h1>Title</h1><h2>Chapter</h2><img src="a.png" alt=""/></body></html>`, 0},
+		{`<html xml:lang="en"><body><table><tr><th>A</th></tr><tr><td>1</td></tr></table></body></html>`, 0},
+		{`<html><body><p>Hello</p></body></html>`, 1},
+		{`<html lang="en"><body><img src="a.png"/></body></html>`, 1},
+		{`<html lang="en"><body><h1>Title</h1><h3>Section</h3></body></html>`, 1},
+		{`<html lang="en"><body><h2>Chapter</h2><h3>Section</h3></body></html>`, 0},
+		{`<html lang="en"><body><h2>Chapter</h2><h4>Section</h4></body></html>`, 1},
+		{`<html lang="en"><body><table><tr><td>1</td></tr></table></body></html>`, 1},
+		{`<html lang="en"><body><table><tr><th>A</th></tr><tr><td><table><tr><td>1</td></tr></table></td></tr></table></body></html>`, 1},
+		{`<html lang="en"><body><table><tr><td><table><tr><th>A</th></tr></table></td></tr></table></body></html>`, 1},
+		{`<html lang="en"><body><table><tr><th>A</th></tr><tr><td><table/></td></tr></table></body></html>`, 0},
+		{`<html lang="en"><head><script src="a.js"/></head><body><img src="a.png"/></body></html>`, 1},
+	}
+
+	for _, tc := range testCases {
+		got, err := InspectAccessibility(strings.NewReader(tc.in))
+		if err != nil {
+			t.Errorf("Fail to inspect accessibility of '%s': %v", tc.in, err)
+			continue
+		}
+
+		if len(got.Issues) != tc.want {
+			t.Errorf("Accessibility inspection of '%s' failed.\nWant %d issue(s)\nGot : %v", tc.in, tc.want, got.Issues)
+		}
+	}
+
+	t.Run("Navigation", func(t *testing.T) {
+		in := `<html lang="en"><body><nav epub:type="toc"></nav><nav epub:type="landmarks"></nav><nav epub:type="page-list"></nav></body></html>`
+
+		got, err := InspectAccessibility(strings.NewReader(in))
+		if err != nil {
+			t.Fatalf("Fail to inspect accessibility of '%s': %v", in, err)
+		}
+
+		if !got.Landmarks || !got.PageList {
+			t.Errorf("Fail to detect landmarks or page-list in '%s': got %+v", in, got)
+		}
+	})
+}
@@ -152,6 +152,7 @@ func (r Rule) sprintAtRuleWithSelector() string {
 		}
 		s.WriteString(sel.String())
 	}
+	s.WriteString(";")
 
 	return s.String()
 }
@@ -97,6 +97,13 @@ func TestRuleString(t *testing.T) {
     }
 }`,
 		},
+		{
+			in: &Rule{
+				AtKeyword: str2token("@charset")[0],
+				Selectors: []Value{str2val(`"UTF-8"`)},
+			},
+			want: `@charset "UTF-8";`,
+		},
 	}
 
 	for _, tc := range testCases {
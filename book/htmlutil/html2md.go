@@ -0,0 +1,335 @@
+package htmlutil
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// asciiSpaces is the set of HTML white spaces that are collapsed. Other
+// unicode spaces (like non-breaking spaces) are meaningful and kept.
+const asciiSpaces = " \t\n\r\f"
+
+var (
+	// reHTMLSpaces is a regexp that matches a sequence of HTML white spaces.
+	reHTMLSpaces = regexp.MustCompile(`[ \t\n\r\f]+`)
+
+	// markdownBlockElements lists the HTML elements that are rendered as
+	// Markdown blocks (separated from their siblings by blank lines).
+	markdownBlockElements = map[atom.Atom]bool{
+		atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true,
+		atom.Body: true, atom.Canvas: true, atom.Dd: true, atom.Div: true, atom.Dl: true,
+		atom.Dt: true, atom.Fieldset: true, atom.Figcaption: true, atom.Figure: true,
+		atom.Footer: true, atom.Form: true, atom.H1: true, atom.H2: true, atom.H3: true,
+		atom.H4: true, atom.H5: true, atom.H6: true, atom.Head: true, atom.Header: true,
+		atom.Hr: true, atom.Html: true, atom.Li: true, atom.Main: true, atom.Nav: true,
+		atom.Noscript: true, atom.Ol: true, atom.P: true, atom.Pre: true, atom.Script: true,
+		atom.Section: true, atom.Style: true, atom.Table: true, atom.Template: true,
+		atom.Title: true, atom.Ul: true, atom.Video: true,
+	}
+)
+
+// GetMarkdownFromHTML converts an HTML document to Markdown, preserving its
+// simple formatting directives: paragraphs, line breaks, headings, lists,
+// emphasis, links, block quotes, preformatted text and tables.
+//
+// Limitation: GetMarkdownFromHTML targets short HTML snippets like books'
+// descriptions. It does not escape Markdown special characters found in the
+// text and ignores images, forms and any styling information.
+func GetMarkdownFromHTML(r io.Reader) (io.Reader, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(markdownFromBlocks(root, "\n\n")), nil
+}
+
+// markdownFromBlocks converts the children of a node to Markdown. Sequences of
+// inline children are gathered into paragraphs, paragraphs and blocks are
+// separated by sep.
+func markdownFromBlocks(n *html.Node, sep string) string {
+	var blocks []string
+	var inline strings.Builder
+
+	flushInline := func() {
+		if p := markdownParagraph(inline.String()); p != "" {
+			blocks = append(blocks, p)
+		}
+		inline.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && markdownBlockElements[c.DataAtom] {
+			flushInline()
+			if b := markdownFromBlock(c); b != "" {
+				blocks = append(blocks, b)
+			}
+			continue
+		}
+
+		inline.WriteString(markdownFromInline(c))
+	}
+	flushInline()
+
+	return strings.Join(blocks, sep)
+}
+
+// markdownFromBlock converts an HTML block element to Markdown.
+func markdownFromBlock(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.Head, atom.Script, atom.Style, atom.Template, atom.Title:
+		return ""
+
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		title := strings.Join(strings.Fields(markdownFromBlocks(n, " ")), " ")
+		if title == "" {
+			return ""
+		}
+		return strings.Repeat("#", headingLevel[n.DataAtom]) + " " + title
+
+	case atom.Ul, atom.Ol:
+		return markdownList(n)
+
+	case atom.Blockquote:
+		return prefixLines(markdownFromBlocks(n, "\n\n"), "> ", ">")
+
+	case atom.Pre:
+		code := strings.Trim(textContent(n), "\n")
+		if code == "" {
+			return ""
+		}
+		return "```\n" + code + "\n```"
+
+	case atom.Hr:
+		return "---"
+
+	case atom.Table:
+		return markdownTable(n)
+
+	default:
+		return markdownFromBlocks(n, "\n\n")
+	}
+}
+
+// markdownFromInline converts an HTML inline element or text to Markdown.
+// Line breaks are rendered as '\n' and are turned into Markdown hard breaks
+// by markdownParagraph.
+func markdownFromInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return reHTMLSpaces.ReplaceAllString(n.Data, " ")
+
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Br:
+			return "\n"
+
+		case atom.Script, atom.Style, atom.Img:
+			return ""
+
+		case atom.Em, atom.I, atom.Cite, atom.Dfn, atom.Var:
+			return emphasize(markdownFromInlines(n), "*")
+
+		case atom.Strong, atom.B:
+			return emphasize(markdownFromInlines(n), "**")
+
+		case atom.Del, atom.S, atom.Strike:
+			return emphasize(markdownFromInlines(n), "~~")
+
+		case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+			return emphasize(markdownFromInlines(n), "`")
+
+		case atom.A:
+			txt, href := markdownFromInlines(n), getNodeAttr(n, "href")
+			if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+				return txt
+			}
+			if strings.Trim(txt, asciiSpaces) == "" {
+				return txt + "<" + href + ">"
+			}
+			return emphasize(txt, "[", "]("+href+")")
+
+		default:
+			return markdownFromInlines(n)
+		}
+	}
+
+	return ""
+}
+
+// markdownFromInlines converts the children of an inline element to Markdown.
+func markdownFromInlines(n *html.Node) string {
+	var txt strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		txt.WriteString(markdownFromInline(c))
+	}
+	return txt.String()
+}
+
+// markdownParagraph collapses spaces of a Markdown paragraph and turns its
+// remaining '\n' into Markdown hard line breaks.
+func markdownParagraph(txt string) string {
+	var lines []string
+	for _, l := range strings.Split(txt, "\n") {
+		if l = reHTMLSpaces.ReplaceAllString(strings.Trim(l, asciiSpaces), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "  \n")
+}
+
+// markdownList converts an HTML list to a Markdown list. Content of list items
+// that spans several lines is indented to be kept within the item.
+func markdownList(n *html.Node) string {
+	var items []string
+
+	idx := 1
+	if start, err := strconv.Atoi(getNodeAttr(n, "start")); err == nil {
+		idx = start
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		if c.DataAtom != atom.Li {
+			// Lists directly nested in a list belong to the previous item.
+			if b := markdownFromBlock(c); b != "" && len(items) > 0 {
+				items[len(items)-1] += "\n" + prefixLines(b, "  ", "")
+			}
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", idx)
+			idx++
+		}
+
+		indent := strings.Repeat(" ", len(marker))
+		item := prefixLines(markdownFromBlocks(c, "\n"), indent, "")
+		items = append(items, marker+strings.TrimPrefix(item, indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+// markdownTable converts an HTML table to a Markdown table. The first row of
+// the table is used as the table's header.
+func markdownTable(n *html.Node) string {
+	var rows [][]string
+	var cols int
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(c)
+
+			case atom.Tr:
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Th || cell.DataAtom == atom.Td {
+						txt := strings.Join(strings.Fields(markdownFromBlocks(cell, " ")), " ")
+						row = append(row, strings.ReplaceAll(txt, "|", `\|`))
+					}
+				}
+
+				if len(row) > 0 {
+					rows = append(rows, row)
+					if len(row) > cols {
+						cols = len(row)
+					}
+				}
+			}
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		for len(row) < cols {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", cols))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// emphasize surrounds a Markdown text with the given markers. Spaces at the
+// text's boundaries are kept outside of the markers.
+func emphasize(txt string, markers ...string) string {
+	open, closing := markers[0], markers[0]
+	if len(markers) > 1 {
+		closing = markers[1]
+	}
+
+	trimmed := strings.Trim(txt, asciiSpaces)
+	if trimmed == "" {
+		return txt
+	}
+
+	lead := txt[:len(txt)-len(strings.TrimLeft(txt, asciiSpaces))]
+	trail := txt[len(strings.TrimRight(txt, asciiSpaces)):]
+	return lead + open + trimmed + closing + trail
+}
+
+// prefixLines adds a prefix to each line of a text, empty lines are prefixed
+// by emptyPrefix.
+func prefixLines(txt, prefix, emptyPrefix string) string {
+	if txt == "" {
+		return ""
+	}
+
+	lines := strings.Split(txt, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = emptyPrefix
+			continue
+		}
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textContent returns the text of a node and all its descendants without
+// altering its spaces.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var txt strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		txt.WriteString(textContent(c))
+	}
+	return txt.String()
+}
+
+// getNodeAttr retrieves the value of a node's attribute. It returns an empty
+// string if attribute does not exist.
+func getNodeAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,120 @@
+package htmlutil
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pirmd/verify"
+)
+
+func TestGetMarkdownFromHTML(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "<p>Hello Gophers!</p>",
+			want: "Hello Gophers!",
+		},
+		{
+			in: `
+			<div>
+				<p>Hello Gophers!</p>
+				<p>Golang is nice</p>
+			</div>`,
+			want: "Hello Gophers!\n\nGolang is nice",
+		},
+		{
+			in:   "<p>Hello<br/>Gophers!</p>",
+			want: "Hello  \nGophers!",
+		},
+		{
+			in:   `Hello <i>Gophers</i>, <b> golang</b> is <em>so</em> <strong>nice</strong>!`,
+			want: `Hello *Gophers*, **golang** is *so* **nice**!`,
+		},
+		{
+			in:   `Run <code>go test</code>, <del>not make</del>`,
+			want: "Run `go test`, ~~not make~~",
+		},
+		{
+			in:   `<h1>Hello <i>Gophers</i>!</h1><h3>Chapter 1</h3>`,
+			want: "# Hello *Gophers*!\n\n### Chapter 1",
+		},
+		{
+			in:   `Visit <a href="https://go.dev/">Go's site</a> or <a href="https://pkg.go.dev/"></a>`,
+			want: "Visit [Go's site](https://go.dev/) or <https://pkg.go.dev/>",
+		},
+		{
+			in:   `<a href="#note1">Note</a> <a href="javascript:alert(42)">Link</a>`,
+			want: `Note Link`,
+		},
+		{
+			in: `
+            <ul>
+            <li>item1
+                <ol>
+                    <li>item1.1</li>
+                    <li>item1.2</li>
+                </ol>
+            </li>
+            <li>item2</li>
+            </ul>`,
+			want: "- item1\n  1. item1.1\n  2. item1.2\n- item2",
+		},
+		{
+			in:   `<ol start="3"><li>third</li><li>fourth</li></ol>`,
+			want: "3. third\n4. fourth",
+		},
+		{
+			in:   `<blockquote><p>To be,</p><p>or not to be</p></blockquote><p>Shakespeare</p>`,
+			want: "> To be,\n>\n> or not to be\n\nShakespeare",
+		},
+		{
+			in:   "<pre>func main() {\n\tfmt.Println(42)\n}</pre>",
+			want: "```\nfunc main() {\n\tfmt.Println(42)\n}\n```",
+		},
+		{
+			in: `
+            <table>
+            <thead><tr><th>Col1</th><th>Col2</th></tr></thead>
+            <tbody>
+            <tr><td>Col1.1</td><td>Col2|1</td></tr>
+            <tr><td>Col1.2</td></tr>
+            </tbody>
+            </table>`,
+			want: "| Col1 | Col2 |\n| --- | --- |\n| Col1.1 | Col2\\|1 |\n| Col1.2 |  |",
+		},
+		{
+			in: `
+            <p>Hello Gophers!</p>
+            <hr/>
+            <script type='text/javascript'>
+            really_useful_stuff();
+            </script>
+            <span>Golang is nice</span>`,
+			want: "Hello Gophers!\n\n---\n\nGolang is nice",
+		},
+		{
+			in:   `<p class="p1">11 &ndash; X A12616 ISBN 978-2-07-012616-3&nbsp;13,90€</p>`,
+			want: "11 – X A12616 ISBN 978-2-07-012616-3 13,90€",
+		},
+	}
+
+	for _, tc := range testCases {
+		gotR, err := GetMarkdownFromHTML(strings.NewReader(tc.in))
+		if err != nil {
+			t.Errorf("Fail to convert '%v' to markdown: %v", tc.in, err)
+			continue
+		}
+
+		got, err := io.ReadAll(gotR)
+		if err != nil {
+			t.Errorf("Fail to convert '%v' to markdown: %v", tc.in, err)
+		}
+
+		if failure := verify.Equal(string(got), tc.want); failure != nil {
+			t.Errorf("Fail to convert '%v' to markdown:\n%v", tc.in, failure)
+		}
+	}
+}
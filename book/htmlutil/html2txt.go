@@ -65,7 +65,7 @@ func getRawTextFromNode(root *html.Node) []byte {
 
 				//TODO: tries to extract `alt` attributes content?
 
-			case atom.Script:
+			case atom.Head, atom.Script, atom.Style:
 				//ignore
 
 				// Mainly Inline elements, maybe more but hopefully not a problem for our use-case
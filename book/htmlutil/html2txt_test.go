@@ -17,6 +17,10 @@ func TestGetRawTextFromHTML(t *testing.T) {
 			in:   "<p>Hello Gophers!</p>",
 			want: "Hello Gophers!",
 		},
+		{
+			in:   "<html><head><title>Title</title><style>p {color: red;}</style></head><body><p>Hello Gophers!</p></body></html>",
+			want: "Hello Gophers!",
+		},
 		{
 			in: `
 			<div>
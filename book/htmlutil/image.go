@@ -0,0 +1,45 @@
+package htmlutil
+
+import (
+	"io"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// GetImagesFromHTML lists, in order of appearance, the location of images
+// referenced by an HTML document either as an HTML img or as an SVG image.
+func GetImagesFromHTML(r io.Reader) ([]string, error) {
+	var images []string
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		if tokenizer.Next() == html.ErrorToken {
+			if err := tokenizer.Err(); err != nil {
+				if err == io.EOF {
+					return images, nil
+				}
+				return images, err
+			}
+		}
+
+		token := tokenizer.Token()
+		if token.Type != html.StartTagToken && token.Type != html.SelfClosingTagToken {
+			continue
+		}
+
+		switch {
+		case token.DataAtom == atom.Img:
+			if src := getAttr(token, "src"); src != "" {
+				images = append(images, src)
+			}
+
+		case token.DataAtom == atom.Image:
+			if href := getAttr(token, "xlink:href"); href != "" {
+				images = append(images, href)
+			} else if href := getAttr(token, "href"); href != "" {
+				images = append(images, href)
+			}
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package htmlutil
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/pirmd/libro/book/htmlutil/css"
+)
+
+// removedWithContentTags lists the tags whose content is removed together
+// with them when they are not allowed. Their content is either not meant to
+// be displayed as text (scripts, embedded objects...) or is not parsed as HTML
+// by the tokenizer so that keeping it would re-inject unchecked markup.
+var removedWithContentTags = map[atom.Atom]bool{
+	atom.Applet:    true,
+	atom.Frameset:  true,
+	atom.Iframe:    true,
+	atom.Math:      true,
+	atom.Noembed:   true,
+	atom.Noframes:  true,
+	atom.Noscript:  true,
+	atom.Object:    true,
+	atom.Plaintext: true,
+	atom.Script:    true,
+	atom.Style:     true,
+	atom.Svg:       true,
+	atom.Template:  true,
+	atom.Textarea:  true,
+	atom.Title:     true,
+	atom.Xmp:       true,
+}
+
+// Sanitize copies the HTML read from r to w, removing what Scan would report
+// as an issue:
+//   - tags that are unknown or not allowed are removed, their content is kept
+//     unless it is not meant to be displayed (like scripts or embedded
+//     objects),
+//   - allowed SVG or MathML content is kept but for its scripts, embedded
+//     HTML content, event handlers and URL whose scheme is not allowed,
+//   - attributes that do not match AllowedTags patterns are removed. Inline
+//     styles are first cleaned from their offending declarations,
+//   - style sheets are cleaned from their offending rules and declarations,
+//   - suspicious comments and unparsed HTML are removed.
+//
+// Content that does not need to be modified is copied untouched.
+// Sanitize returns a list of messages describing what was removed.
+func (s *Scanner) Sanitize(r io.Reader, w io.Writer) ([]string, error) {
+	var report []string
+	reportRemoval := func(format string, a ...interface{}) {
+		report = append(report, fmt.Sprintf(format, a...))
+	}
+
+	var inStyleNode bool
+	// skipTag is the tag whose content is being removed and skipDepth the
+	// nesting level of skipTag.
+	var skipTag string
+	var skipDepth int
+	var foreignCtnt foreignContent
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		if nextToken(tokenizer) == html.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return report, err
+			}
+
+			if notparsed := tokenizer.Raw(); len(notparsed) > 0 {
+				reportRemoval("Unparsed HTML found: %s: removed", string(notparsed))
+			}
+			return report, nil
+		}
+
+		// Raw is copied before calling Token that might modify it.
+		raw := append([]byte(nil), tokenizer.Raw()...)
+		token := tokenizer.Token()
+
+		if skipDepth > 0 {
+			switch {
+			case token.Type == html.StartTagToken && token.Data == skipTag:
+				skipDepth++
+			case token.Type == html.EndTagToken && token.Data == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		var out []byte
+		switch token.Type {
+		case html.DoctypeToken:
+			out = raw
+
+		case html.CommentToken:
+			if reConditionalOrSSIComment.MatchString(token.Data) {
+				reportRemoval("Suspicious directive hidden in a comment: %s: removed", token.Data)
+				continue
+			}
+			out = raw
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			inStyleNode = false
+
+			foreign := foreignCtnt.in() || s.allowsForeignContent(token.DataAtom)
+			switch {
+			case foreign:
+				if issue := inspectForeignTag(token); issue != "" {
+					if token.Type == html.StartTagToken {
+						reportRemoval("%s: removed with its content", issue)
+						skipTag, skipDepth = token.Data, 1
+						continue
+					}
+					reportRemoval("%s: removed", issue)
+					continue
+				}
+
+				if token.Type == html.StartTagToken {
+					foreignCtnt.start(token)
+				}
+
+			case token.DataAtom == 0:
+				reportRemoval("Tag '%s' is unknown: removed", token.Data)
+				continue
+
+			default:
+				if _, isAllowed := s.AllowedTags[token.DataAtom]; !isAllowed {
+					if token.Type == html.StartTagToken && removedWithContentTags[token.DataAtom] {
+						reportRemoval("Tag '%s' is not allowed: removed with its content", token.Data)
+						skipTag, skipDepth = token.Data, 1
+						continue
+					}
+
+					reportRemoval("Tag '%s' is not allowed: removed", token.Data)
+					continue
+				}
+			}
+
+			inStyleNode = (token.Type == html.StartTagToken && token.DataAtom == atom.Style)
+
+			attrs, removed := s.sanitizeAttrs(token, foreign)
+			if len(removed) == 0 {
+				out = raw
+				break
+			}
+			report = append(report, removed...)
+
+			token.Attr = attrs
+			out = []byte(tagString(token, raw))
+
+		case html.EndTagToken:
+			inStyleNode = false
+
+			if !foreignCtnt.end(token) {
+				if token.DataAtom == 0 {
+					continue
+				}
+
+				if _, isAllowed := s.AllowedTags[token.DataAtom]; !isAllowed {
+					continue
+				}
+			}
+
+			if len(token.String()) != len(raw) {
+				reportRemoval("Closing tag seems to contain unexpected data: %s: removed", string(raw))
+				out = []byte(tagString(token, raw))
+				break
+			}
+			out = raw
+
+		case html.TextToken:
+			if !inStyleNode {
+				out = raw
+				break
+			}
+
+			cssTxt, removed, err := s.sanitizeCSS(token.Data)
+			if err != nil {
+				reportRemoval("fail to inspect CSS declaration '%s': %v: removed", token.Data, err)
+				continue
+			}
+
+			if len(removed) == 0 {
+				out = raw
+				break
+			}
+			report = append(report, removed...)
+			out = []byte(cssTxt)
+
+		default:
+			reportRemoval("Unknown token: %v: removed", string(raw))
+			continue
+		}
+
+		if _, err := w.Write(out); err != nil {
+			return report, err
+		}
+	}
+}
+
+// SanitizeCSS copies the CSS style sheet read from r to w, removing rules and
+// declarations that ScanCSS would report as an issue. If nothing needs to be
+// removed, the style sheet is copied untouched.
+// SanitizeCSS returns a list of messages describing what was removed.
+func (s *Scanner) SanitizeCSS(r io.Reader, w io.Writer) ([]string, error) {
+	cssTxt := new(strings.Builder)
+	if _, err := io.Copy(cssTxt, r); err != nil {
+		return nil, err
+	}
+
+	clean, report, err := s.sanitizeCSS(cssTxt.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.WriteString(w, clean); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// sanitizeAttrs returns the attributes of a tag that match AllowedTags
+// patterns or, for SVG or MathML content (foreign is set), that pass
+// inspectForeignAttr. Inline styles are cleaned from their offending
+// declarations rather than removed.
+func (s *Scanner) sanitizeAttrs(inTag html.Token, foreign bool) (attrs []html.Attribute, report []string) {
+	inspect := func(attr html.Attribute) []string {
+		if foreign {
+			return s.inspectForeignAttr(attr)
+		}
+		return s.inspectAttr(inTag, attr)
+	}
+
+	for _, attr := range inTag.Attr {
+		issues := inspect(attr)
+		if len(issues) == 0 {
+			attrs = append(attrs, attr)
+			continue
+		}
+
+		if attr.Key == "style" {
+			cssTxt, removed, err := s.sanitizeInlineCSS(attr.Val)
+			if err == nil && cssTxt != "" {
+				cleanAttr := html.Attribute{Namespace: attr.Namespace, Key: attr.Key, Val: cssTxt}
+				if len(inspect(cleanAttr)) == 0 {
+					attrs = append(attrs, cleanAttr)
+					for _, r := range removed {
+						report = append(report, fmt.Sprintf("%s=%s: %s", attr.Key, attr.Val, r))
+					}
+					continue
+				}
+			}
+		}
+
+		report = append(report, fmt.Sprintf("%s=%s: %s: removed", attr.Key, attr.Val, strings.Join(issues, ", ")))
+	}
+
+	return
+}
+
+// sanitizeCSS returns the CSS style sheet without its offending rules and
+// declarations. If nothing needs to be removed, the style sheet is returned
+// untouched.
+func (s *Scanner) sanitizeCSS(cssTxt string) (clean string, report []string, err error) {
+	ruleset, err := css.Parse(cssTxt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanRuleset, report := s.sanitizeCSSRuleset(ruleset)
+	if len(report) == 0 {
+		return cssTxt, nil, nil
+	}
+
+	return cleanRuleset.String(), report, nil
+}
+
+// sanitizeInlineCSS returns an inline style without its offending
+// declarations. If nothing needs to be removed, the style is returned
+// untouched.
+func (s *Scanner) sanitizeInlineCSS(cssTxt string) (clean string, report []string, err error) {
+	ruleset, err := css.ParseInline(cssTxt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanRuleset, report := s.sanitizeCSSRuleset(ruleset)
+	if len(report) == 0 {
+		return cssTxt, nil, nil
+	}
+
+	var decls []string
+	for _, rule := range cleanRuleset {
+		for _, decl := range rule.Declarations {
+			decls = append(decls, decl.String())
+		}
+	}
+
+	return strings.Join(decls, "; "), report, nil
+}
+
+// sanitizeCSSRuleset returns the rules that are allowed, cleaned from their
+// offending declarations and nested rules. Rules that are left empty are
+// removed.
+func (s *Scanner) sanitizeCSSRuleset(ruleset css.Ruleset) (clean css.Ruleset, report []string) {
+	for _, rule := range ruleset {
+		if issue := s.inspectCSSRuleHeader(rule); issue != "" {
+			report = append(report, fmt.Sprintf("%s: rule removed", issue))
+			continue
+		}
+
+		cleanRule := *rule
+		cleanRule.Declarations = nil
+		for _, decl := range rule.Declarations {
+			if issue := s.inspectCSSDeclaration(decl); issue != "" {
+				report = append(report, fmt.Sprintf("%s: %s: removed", decl, issue))
+				continue
+			}
+			cleanRule.Declarations = append(cleanRule.Declarations, decl)
+		}
+
+		if len(rule.EmbeddedRuleset) > 0 {
+			var removed []string
+			cleanRule.EmbeddedRuleset, removed = s.sanitizeCSSRuleset(rule.EmbeddedRuleset)
+			report = append(report, removed...)
+		}
+
+		if (len(rule.Declarations) > 0 && len(cleanRule.Declarations) == 0) ||
+			(len(rule.EmbeddedRuleset) > 0 && len(cleanRule.EmbeddedRuleset) == 0) {
+			continue
+		}
+
+		clean = append(clean, &cleanRule)
+	}
+
+	return
+}
+
+// tagString returns the string representation of a (modified) tag token.
+// The tokenizer lower-cases tag and attributes names, original names are
+// restored from the tag's raw text as SVG is case-sensitive (like viewBox).
+func tagString(token html.Token, raw []byte) string {
+	token.Data = restoreCase(raw, token.Data)
+
+	attrs := make([]html.Attribute, len(token.Attr))
+	for i, attr := range token.Attr {
+		attr.Key = restoreCase(raw, attr.Key)
+		attrs[i] = attr
+	}
+	token.Attr = attrs
+
+	return token.String()
+}
+
+// restoreCase looks for the original spelling of a lower-cased tag or
+// attribute name in the tag's raw text.
+func restoreCase(raw []byte, name string) string {
+	lower := asciiLower(raw)
+	isDelim := func(c byte) bool { return strings.IndexByte(asciiSpaces+"</=>", c) >= 0 }
+
+	for i := 0; i < len(lower); {
+		j := strings.Index(lower[i:], name)
+		if j < 0 {
+			break
+		}
+		j += i
+
+		if end := j + len(name); j > 0 && isDelim(raw[j-1]) && (end == len(raw) || isDelim(raw[end])) {
+			return string(raw[j:end])
+		}
+		i = j + 1
+	}
+
+	return name
+}
+
+// asciiLower lower-cases ASCII letters only so that positions in the
+// lower-cased text match the original one.
+func asciiLower(b []byte) string {
+	lower := make([]byte, len(b))
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower[i] = c
+	}
+	return string(lower)
+}
@@ -0,0 +1,220 @@
+package htmlutil
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html/atom"
+)
+
+func TestSanitize(t *testing.T) {
+	testScanner := NewScannerWithStyle()
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   `<p class="foo">Hello <b>World</b>&nbsp;!</p>`,
+			want: `<p class="foo">Hello <b>World</b>&nbsp;!</p>`,
+		},
+		{
+			in:   `<p>Hello<script>alert('XSS')</script> World</p>`,
+			want: `<p>Hello World</p>`,
+		},
+		{
+			in:   `<p>Hello<script src="xss.js"/><img src="x.png" onerror="alert('XSS')"/></p>`,
+			want: `<p>Hello<img src="x.png"/></p>`,
+		},
+		{
+			in:   `<p>Hello <font color="red">World</font></p>`,
+			want: `<p>Hello World</p>`,
+		},
+		{
+			in:   `<p onclick="alert('XSS')" class="foo">Hello</p>`,
+			want: `<p class="foo">Hello</p>`,
+		},
+		{
+			in:   `<a href="javascript:alert('XSS')">Hello</a>`,
+			want: `<a>Hello</a>`,
+		},
+		{
+			in:   `<div style="color: red; width: expression(alert('XSS'))">Hello</div>`,
+			want: `<div style="color: red">Hello</div>`,
+		},
+		{
+			in:   `<style>p { color: red; behavior: url(xss.htc) } @import url(xss.css);</style>`,
+			want: "<style>p {\n    color: red;\n}</style>",
+		},
+		{
+			in:   `<p>Hello<!--#exec cmd="/bin/echo XSS"--> World</p>`,
+			want: `<p>Hello World</p>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := new(strings.Builder)
+		report, err := testScanner.Sanitize(strings.NewReader(tc.in), got)
+		if err != nil {
+			t.Errorf("Sanitize of '%s' failed: %v", tc.in, err)
+			continue
+		}
+
+		if got.String() != tc.want {
+			t.Errorf("Sanitize of '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+
+		if (len(report) == 0) != (tc.in == tc.want) {
+			t.Errorf("Sanitize of '%s' reported wrong removals: %v", tc.in, report)
+		}
+	}
+}
+
+func TestSanitizeForeignContent(t *testing.T) {
+	testScanner := NewPermissiveScanner()
+	testScanner.AllowedTags[atom.Svg] = []string{}
+	testScanner.AllowedTags[atom.Math] = []string{}
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   `<div><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.1" width="100%" height="100%" viewBox="0 0 600 800" preserveAspectRatio="none"><image width="600" height="800" xlink:href="cover.jpeg"/></svg></div>`,
+			want: `<div><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" version="1.1" width="100%" height="100%" viewBox="0 0 600 800" preserveAspectRatio="none"><image width="600" height="800" xlink:href="cover.jpeg"/></svg></div>`,
+		},
+		{
+			in:   `<p><math xmlns="http://www.w3.org/1998/Math/MathML"><mi>x</mi><mo>=</mo><mfrac><mn>1</mn><mn>2</mn></mfrac></math></p>`,
+			want: `<p><math xmlns="http://www.w3.org/1998/Math/MathML"><mi>x</mi><mo>=</mo><mfrac><mn>1</mn><mn>2</mn></mfrac></math></p>`,
+		},
+		{
+			in:   `<svg viewBox="0 0 10 10" onload="alert('XSS')"><script>alert('XSS')</script><script xlink:href="xss.js"/><a xlink:href="javascript:alert('XSS')"><text>Hello</text></a><foreignObject><p>Hello</p></foreignObject></svg><p>World</p>`,
+			want: `<svg viewBox="0 0 10 10"><a><text>Hello</text></a></svg><p>World</p>`,
+		},
+		{
+			in:   `<svg><g></svg><form action="x.html"><input/></form><meta http-equiv="refresh" content="0;url=x.html"/>`,
+			want: `<svg><g></svg><meta content="0;url=x.html"/>`,
+		},
+		{
+			in:   `<math><maction actiontype="statusline" href="javascript:alert('XSS')"><mi>x</mi></maction></math>`,
+			want: `<math><maction actiontype="statusline"><mi>x</mi></maction></math>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := new(strings.Builder)
+		report, err := testScanner.Sanitize(strings.NewReader(tc.in), got)
+		if err != nil {
+			t.Errorf("Sanitize of '%s' failed: %v", tc.in, err)
+			continue
+		}
+
+		if got.String() != tc.want {
+			t.Errorf("Sanitize of '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+
+		if (len(report) == 0) != (tc.in == tc.want) {
+			t.Errorf("Sanitize of '%s' reported wrong removals: %v", tc.in, report)
+		}
+
+		if issues, err := testScanner.Scan(strings.NewReader(tc.in)); err != nil || (len(issues) == 0) != (tc.in == tc.want) {
+			t.Errorf("Scan of '%s' is not consistent with Sanitize: %v (err: %v)", tc.in, issues, err)
+		}
+	}
+}
+
+func TestSanitizeCSS(t *testing.T) {
+	testScanner := NewScannerWithStyle()
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{
+			in:   "body { color: red }\n",
+			want: "body { color: red }\n",
+		},
+		{
+			in:   `@import url(xss.css); body { color: red; -moz-binding: url(xss.xml) } p { behavior: url(xss.htc) }`,
+			want: "body {\n    color: red;\n}",
+		},
+	}
+
+	for _, tc := range testCases {
+		got := new(strings.Builder)
+		if _, err := testScanner.SanitizeCSS(strings.NewReader(tc.in), got); err != nil {
+			t.Errorf("SanitizeCSS of '%s' failed: %v", tc.in, err)
+			continue
+		}
+
+		if got.String() != tc.want {
+			t.Errorf("SanitizeCSS of '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestSanitizeXSS(t *testing.T) {
+	for name, scanner := range map[string]*Scanner{
+		"Minimal":    NewMinimalScanner(),
+		"WithStyle":  NewScannerWithStyle(),
+		"Permissive": NewPermissiveScanner(),
+		"WithForeignContent": func() *Scanner {
+			s := NewPermissiveScanner()
+			s.AllowedTags[atom.Svg] = []string{}
+			s.AllowedTags[atom.Math] = []string{}
+			return s
+		}(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			testSanitizerWithRule(t, scanner)
+		})
+	}
+}
+
+func testSanitizerWithRule(t *testing.T, scanner *Scanner) {
+	files, err := filepath.Glob(filepath.Join(testData, "*"))
+	if err != nil {
+		t.Fatalf("cannot read test data in %s: %v", testData, err)
+	}
+
+	for _, path := range files {
+		r, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("cannot read test data in %s: %v", path, err)
+		}
+		defer r.Close()
+
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			line := 0
+			s := bufio.NewScanner(r)
+			for s.Scan() {
+				line++
+				tc := s.Text()
+				if tc == "" || strings.HasPrefix(tc, "//") {
+					continue // empty line or comment
+				}
+
+				clean := new(strings.Builder)
+				if _, err := scanner.Sanitize(strings.NewReader(tc), clean); err != nil {
+					t.Errorf("[line %d] Sanitize of '%s' failed: %v", line, tc, err)
+					continue
+				}
+
+				issues, err := scanner.Scan(strings.NewReader(clean.String()))
+				if err != nil {
+					t.Errorf("[line %d] Scan of sanitized '%s' failed: %v", line, clean, err)
+				}
+
+				if issues != nil {
+					for _, msg := range issues {
+						t.Log(msg)
+					}
+					t.Errorf("[line %d] Sanitized '%s' does not pass scan: '%s'.", line, tc, clean)
+				}
+			}
+		})
+	}
+}
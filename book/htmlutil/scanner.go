@@ -28,6 +28,18 @@ var (
 	// reAnonymousHost matches suspicious HOST identified by an IP
 	// address only.
 	reAnonymousHost = regexp.MustCompile(`^[\\.0-9]+$`)
+
+	// foreignUnsafeTags lists SVG or MathML elements that run scripts or embed
+	// HTML content (tag names are lower-cased by the tokenizer).
+	foreignUnsafeTags = map[string]bool{
+		"embed":         true,
+		"foreignobject": true,
+		"handler":       true,
+		"iframe":        true,
+		"listener":      true,
+		"object":        true,
+		"script":        true,
+	}
 )
 
 // Scanner represents an HTML/CSS scanner that looks for possible security
@@ -69,6 +81,12 @@ type Scanner struct {
 	// accepted, so catch-all patterns are actually quite tedious to use.
 	// TODO: As off now, it is a "good enough" approach but probably needs further
 	// polishing/rework to make something acceptable out of this.
+	//
+	// atom.Svg and atom.Math allow SVG and MathML content. Their vocabularies
+	// are not checked against AllowedTags (patterns are ignored): any element
+	// or attribute is accepted except scripts, embedded HTML content, event
+	// handlers and URL whose scheme is not allowed (see inspectForeignTag and
+	// inspectForeignAttr).
 	AllowedTags map[atom.Atom][]string
 
 	// AllowedURLSchemes is the white-list of allowed schemes in URL.
@@ -97,6 +115,11 @@ type Scanner struct {
 	// "*" allows any keywords, "!xxx" failed immediately for keyword xxx even
 	// if keyword xxx is allowed afterwards.
 	AllowedCSSAtKeywords []string
+
+	// IgnoreScripts, when set to true, makes Scan ignore script elements,
+	// event handlers and javascript: URLs so that they can be reported apart
+	// using InspectScripts. Sanitize always removes them.
+	IgnoreScripts bool
 }
 
 // NewMinimalScanner creates a new scanner that allows only minimal HTML
@@ -227,10 +250,11 @@ func (s *Scanner) Scan(r io.Reader) ([]string, error) {
 	}
 
 	var inStyleNode bool
+	var foreign foreignContent
 
 	tokenizer := html.NewTokenizer(r)
 	for {
-		if tokenizer.Next() == html.ErrorToken {
+		if nextToken(tokenizer) == html.ErrorToken {
 			if err := tokenizer.Err(); err != nil {
 				if err == io.EOF {
 					// Tokenizer seems to simply ignore bad formatted HTML.
@@ -256,6 +280,33 @@ func (s *Scanner) Scan(r io.Reader) ([]string, error) {
 			}
 
 		case html.StartTagToken, html.SelfClosingTagToken:
+			if foreign.in() || s.allowsForeignContent(token.DataAtom) {
+				if token.Type == html.StartTagToken {
+					foreign.start(token)
+				}
+
+				inStyleNode = (token.DataAtom == atom.Style)
+
+				if s.IgnoreScripts && token.DataAtom == atom.Script {
+					continue
+				}
+
+				if issue := inspectForeignTag(token); issue != "" {
+					reportIssue(issue)
+					continue
+				}
+
+				for _, attr := range token.Attr {
+					if s.IgnoreScripts && isScriptAttr(attr) {
+						continue
+					}
+					for _, issue := range s.inspectForeignAttr(attr) {
+						reportIssue("%s=%s: %s", attr.Key, attr.Val, issue)
+					}
+				}
+				continue
+			}
+
 			if token.DataAtom == 0 {
 				reportIssue("Tag '%s' is unknown", token.Data)
 				continue
@@ -263,19 +314,26 @@ func (s *Scanner) Scan(r io.Reader) ([]string, error) {
 
 			inStyleNode = (token.DataAtom == atom.Style)
 
+			if s.IgnoreScripts && token.DataAtom == atom.Script {
+				continue
+			}
+
 			if _, isAllowed := s.AllowedTags[token.DataAtom]; !isAllowed {
 				reportIssue("Tag '%s' is not allowed", token.Data)
 				continue
 			}
 
 			for _, attr := range token.Attr {
+				if s.IgnoreScripts && isScriptAttr(attr) {
+					continue
+				}
 				for _, issue := range s.inspectAttr(token, attr) {
 					reportIssue("%s=%s: %s", attr.Key, attr.Val, issue)
 				}
 			}
 
 		case html.EndTagToken:
-			if token.DataAtom == 0 {
+			if !foreign.end(token) && token.DataAtom == 0 {
 				reportIssue("Tag '%s' is unknown", token.Data)
 				continue
 			}
@@ -320,6 +378,88 @@ func (s *Scanner) ScanCSS(r io.Reader) ([]string, error) {
 	return s.inspectCSS(cssTxt.String())
 }
 
+// allowsForeignContent reports whether tag introduces SVG or MathML content
+// that is allowed.
+func (s *Scanner) allowsForeignContent(tag atom.Atom) bool {
+	if tag != atom.Svg && tag != atom.Math {
+		return false
+	}
+	_, isAllowed := s.AllowedTags[tag]
+	return isAllowed
+}
+
+// foreignContent keeps track of SVG or MathML content. Foreign content is
+// only left when the element that introduced it (its root) is closed so that
+// unbalanced tags inside foreign content cannot end it early (or late).
+type foreignContent struct {
+	root  string
+	depth int
+}
+
+// in reports whether tokens are part of foreign content.
+func (f *foreignContent) in() bool {
+	return f.depth > 0
+}
+
+// start records a start tag that is either inside foreign content or that
+// introduces it.
+func (f *foreignContent) start(token html.Token) {
+	switch {
+	case f.depth == 0:
+		f.root, f.depth = token.Data, 1
+	case token.Data == f.root:
+		f.depth++
+	}
+}
+
+// end records an end tag and reports whether it is part of foreign content.
+func (f *foreignContent) end(token html.Token) bool {
+	if f.depth == 0 {
+		return false
+	}
+	if token.Data == f.root {
+		f.depth--
+	}
+	return true
+}
+
+// inspectForeignTag checks an element of SVG or MathML content.
+func inspectForeignTag(token html.Token) string {
+	if foreignUnsafeTags[token.Data] {
+		return fmt.Sprintf("Tag '%s' is not allowed in SVG or MathML content", token.Data)
+	}
+	return ""
+}
+
+// inspectForeignAttr checks an attribute of SVG or MathML content.
+func (s *Scanner) inspectForeignAttr(attr html.Attribute) (issues []string) {
+	switch {
+	case strings.HasPrefix(attr.Key, "on"):
+		return []string{"event handlers are not allowed"}
+
+	case urlAttributes[attr.Key] && isJavascriptURL(attr.Val):
+		return []string{"javascript: URL are not allowed"}
+
+	case attr.Key == "href" || attr.Key == "xlink:href" || attr.Key == "src":
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil {
+			return []string{fmt.Sprintf("non-parsable url are not allowed (%v)", err)}
+		}
+		if u.Scheme != "" && !isInList(u.Scheme, s.AllowedURLSchemes) {
+			return []string{fmt.Sprintf("url scheme '%s' is not allowed", u.Scheme)}
+		}
+
+	case attr.Key == "style":
+		cssIssues, err := s.inspectInlineCSS(attr.Val)
+		if err != nil {
+			return []string{fmt.Sprintf("inline CSS parsing error: %v", err)}
+		}
+		return cssIssues
+	}
+
+	return nil
+}
+
 func (s *Scanner) inspectAttr(inTag html.Token, attr html.Attribute) (issues []string) {
 	// It seems that tokenizer does not detect properly empty attribute values, so
 	// this workaround might be better than nothing
@@ -477,32 +617,46 @@ func (s *Scanner) inspectInlineCSS(cssTxt string) (issues []string, err error) {
 }
 
 func (s *Scanner) inspectCSSRule(rule *css.Rule) (issues []string) {
+	if issue := s.inspectCSSRuleHeader(rule); issue != "" {
+		return []string{issue}
+	}
+
+	for _, decl := range rule.Declarations {
+		if issue := s.inspectCSSDeclaration(decl); issue != "" {
+			return append(issues, issue)
+		}
+	}
+
+	for _, r := range rule.EmbeddedRuleset {
+		issues = append(issues, s.inspectCSSRule(r)...)
+	}
+
+	return issues
+}
+
+// inspectCSSRuleHeader checks a rule's at-keyword and selectors.
+func (s *Scanner) inspectCSSRuleHeader(rule *css.Rule) string {
 	if rule.AtKeyword != nil {
 		if !isInList(rule.AtKeyword.Value, s.AllowedCSSAtKeywords) {
-			return []string{fmt.Sprintf("%s is not an allowed at-keyword", rule.AtKeyword.Value)}
+			return fmt.Sprintf("%s is not an allowed at-keyword", rule.AtKeyword.Value)
 		}
 	}
 
 	for _, val := range rule.Selectors {
 		if issue := s.inspectCSSValue(val); issue != "" {
-			return append(issues, issue)
+			return issue
 		}
 	}
 
-	for _, decl := range rule.Declarations {
-		if !isInList(decl.Property, s.AllowedCSSProperties) {
-			return append(issues, fmt.Sprintf("%s is not an allowed CSS property", decl.Property))
-		}
-		if issue := s.inspectCSSValue(decl.Value); issue != "" {
-			return append(issues, issue)
-		}
-	}
+	return ""
+}
 
-	for _, r := range rule.EmbeddedRuleset {
-		issues = append(issues, s.inspectCSSRule(r)...)
+func (s *Scanner) inspectCSSDeclaration(decl *css.Declaration) string {
+	if !isInList(decl.Property, s.AllowedCSSProperties) {
+		return fmt.Sprintf("%s is not an allowed CSS property", decl.Property)
 	}
 
-	return issues
+	return s.inspectCSSValue(decl.Value)
 }
 
 func (s *Scanner) inspectCSSValue(val css.Value) string {
@@ -564,3 +718,20 @@ func isInList(s string, allowed []string) bool {
 
 	return false
 }
+
+// nextToken scans the next token of a content document. EPUB's content is
+// XHTML where self-closing elements like <script/> have no content: contrary
+// to HTML, what follows them should not be read as their raw text.
+func nextToken(tokenizer *html.Tokenizer) html.TokenType {
+	tt := tokenizer.Next()
+	if tt == html.SelfClosingTagToken {
+		tokenizer.NextIsNotRawText()
+	}
+	return tt
+}
+
+// isScriptAttr reports whether an attribute is an event handler or a
+// javascript: URL.
+func isScriptAttr(attr html.Attribute) bool {
+	return strings.HasPrefix(attr.Key, "on") || (urlAttributes[attr.Key] && isJavascriptURL(attr.Val))
+}
@@ -100,6 +100,62 @@ func TestPermissiveScanner(t *testing.T) {
 	})
 }
 
+func TestScannerWithForeignContent(t *testing.T) {
+	testScanner := NewPermissiveScanner()
+	testScanner.AllowedTags[atom.Svg] = []string{}
+	testScanner.AllowedTags[atom.Math] = []string{}
+
+	t.Run("XSS", func(t *testing.T) {
+		testScannerWithRule(t, testScanner)
+	})
+
+	t.Run("UnbalancedTags", func(t *testing.T) {
+		testCases := []string{
+			`<svg><g></svg><form action="x.html"><input/></form>`,
+			`<svg><g></svg><meta http-equiv="refresh" content="0;url=x.html"/>`,
+			`<math><mrow></math><iframe src="x.html"></iframe>`,
+		}
+
+		for _, tc := range testCases {
+			issues, err := testScanner.Scan(strings.NewReader(tc))
+			if err != nil {
+				t.Errorf("Scan of '%s' failed: %v", tc, err)
+			}
+
+			if len(issues) == 0 {
+				t.Errorf("Scan of '%s' should report issues", tc)
+			}
+		}
+	})
+}
+
+func TestScannerIgnoreScripts(t *testing.T) {
+	testScanner := NewMinimalScanner()
+	testScanner.AllowedTags[atom.Svg] = []string{}
+	testScanner.IgnoreScripts = true
+
+	testCases := []struct {
+		in   string
+		want int
+	}{
+		{`<p onclick="alert('XSS')">Hello</p><script>alert('XSS')</script>`, 0},
+		{`<a href="javascript:alert('XSS')">Hello</a>`, 0},
+		{`<svg><script>alert('XSS')</script><rect onload="alert('XSS')"/></svg>`, 0},
+		{`<script/><p onclick="alert('XSS')">Hello</p><iframe src="x.html"></iframe>`, 1},
+	}
+
+	for _, tc := range testCases {
+		issues, err := testScanner.Scan(strings.NewReader(tc.in))
+		if err != nil {
+			t.Errorf("Scan of '%s' failed: %v", tc.in, err)
+		}
+
+		if len(issues) != tc.want {
+			t.Errorf("Scan of '%s' ignoring scripts failed.\nWant: %d issues\nGot : %v", tc.in, tc.want, issues)
+		}
+	}
+}
+
 func TestScanner(t *testing.T) {
 	// TestScanner uses a slightly less permissive set of rules compared to
 	// ScannerWithStyle for testing purpose.
@@ -0,0 +1,234 @@
+package htmlutil
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// reJavascriptURLinCSS matches CSS url() whose target is a javascript:
+	// URL.
+	reJavascriptURLinCSS = regexp.MustCompile(`(?i)url\(\s*['"]?\s*javascript:`)
+
+	// urlAttributes lists the attributes whose value is an URL (or can be
+	// set to an URL by SVG animations).
+	urlAttributes = map[string]bool{
+		"action": true, "background": true, "by": true, "cite": true,
+		"codebase": true, "data": true, "formaction": true, "from": true,
+		"href": true, "lowsrc": true, "poster": true, "src": true, "to": true,
+		"values": true, "xlink:href": true,
+	}
+)
+
+// ScriptCategory identifies the kind of a scripted content.
+type ScriptCategory int
+
+const (
+	// InlineScript is a script element with embedded code.
+	InlineScript ScriptCategory = iota
+	// ExternalScript is a script element that loads its code from a 'src'
+	// attribute.
+	ExternalScript
+	// EventHandler is an 'onxxx' attribute.
+	EventHandler
+	// JavascriptURL is an attribute or a CSS url() whose target is a
+	// javascript: URL.
+	JavascriptURL
+	// ScriptedSVG is a script, an event handler or a javascript: URL found in
+	// SVG content.
+	ScriptedSVG
+	// ScriptedContent is an EPUB's content document declared as containing
+	// scripts (manifest's item with properties="scripted").
+	ScriptedContent
+)
+
+func (c ScriptCategory) String() string {
+	switch c {
+	case InlineScript:
+		return "inline script"
+	case ExternalScript:
+		return "external script"
+	case EventHandler:
+		return "event handler"
+	case JavascriptURL:
+		return "javascript: URL"
+	case ScriptedSVG:
+		return "scripted SVG"
+	case ScriptedContent:
+		return "scripted content document"
+	default:
+		return fmt.Sprintf("ScriptCategory(%d)", int(c))
+	}
+}
+
+// Script represents a scripted content found in HTML or CSS.
+type Script struct {
+	// Category is the kind of scripted content.
+	Category ScriptCategory
+
+	// Detail is an excerpt of the scripted content.
+	Detail string
+}
+
+func (s Script) String() string {
+	return fmt.Sprintf("%s: %s", s.Category, s.Detail)
+}
+
+// InspectScripts looks for scripts in HTML or SVG content read from r: script
+// elements, event handlers, javascript: URLs in attributes or inline styles,
+// and HTML documents embedded in iframes' 'srcdoc' attribute. Any script
+// found in SVG content is reported as ScriptedSVG.
+//
+// Limitation: like Scanner, InspectScripts does not manage obfuscated
+// content, it is a way to tell that content contains JavaScript rather than a
+// protection against it.
+func InspectScripts(r io.Reader) ([]Script, error) {
+	var scripts []Script
+	var svgDepth int
+	reportScript := func(c ScriptCategory, format string, a ...interface{}) {
+		if svgDepth > 0 {
+			c = ScriptedSVG
+		}
+		scripts = append(scripts, Script{Category: c, Detail: fmt.Sprintf(format, a...)})
+	}
+
+	var inScript, inStyleNode bool
+	var scriptTxt strings.Builder
+
+	tokenizer := html.NewTokenizer(r)
+	for {
+		if nextToken(tokenizer) == html.ErrorToken {
+			if err := tokenizer.Err(); err != nil {
+				if err == io.EOF {
+					return scripts, nil
+				}
+				return scripts, err
+			}
+		}
+
+		switch token := tokenizer.Token(); token.Type {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if token.DataAtom == atom.Svg && token.Type == html.StartTagToken {
+				svgDepth++
+			}
+
+			inStyleNode = (token.DataAtom == atom.Style && token.Type == html.StartTagToken)
+
+			if token.DataAtom == atom.Script {
+				if src := scriptSource(token); src != "" {
+					reportScript(ExternalScript, "script %s", src)
+				} else if token.Type == html.StartTagToken {
+					inScript = true
+					scriptTxt.Reset()
+				}
+			}
+
+			for _, attr := range token.Attr {
+				switch {
+				case strings.HasPrefix(attr.Key, "on"):
+					reportScript(EventHandler, "%s %s=%s", token.Data, attr.Key, shorten(attr.Val))
+
+				case urlAttributes[attr.Key] && isJavascriptURL(attr.Val):
+					reportScript(JavascriptURL, "%s %s=%s", token.Data, attr.Key, shorten(attr.Val))
+
+				case attr.Key == "style" && reJavascriptURLinCSS.MatchString(attr.Val):
+					reportScript(JavascriptURL, "%s style=%s", token.Data, shorten(attr.Val))
+
+				case attr.Key == "srcdoc":
+					embedded, err := InspectScripts(strings.NewReader(attr.Val))
+					if err != nil {
+						return scripts, err
+					}
+					for _, s := range embedded {
+						reportScript(s.Category, "%s srcdoc: %s", token.Data, s.Detail)
+					}
+				}
+			}
+
+		case html.EndTagToken:
+			switch token.DataAtom {
+			case atom.Svg:
+				if svgDepth > 0 {
+					svgDepth--
+				}
+
+			case atom.Script:
+				if inScript {
+					reportScript(InlineScript, "script %s", shorten(scriptTxt.String()))
+					inScript = false
+				}
+
+			case atom.Style:
+				inStyleNode = false
+			}
+
+		case html.TextToken:
+			switch {
+			case inScript:
+				scriptTxt.WriteString(token.Data)
+
+			case inStyleNode:
+				for _, s := range inspectCSSScripts(token.Data) {
+					reportScript(s.Category, "style %s", s.Detail)
+				}
+			}
+		}
+	}
+}
+
+// InspectCSSScripts looks for javascript: URLs in CSS content read from r.
+func InspectCSSScripts(r io.Reader) ([]Script, error) {
+	cssTxt := new(strings.Builder)
+	if _, err := io.Copy(cssTxt, r); err != nil {
+		return nil, err
+	}
+
+	return inspectCSSScripts(cssTxt.String()), nil
+}
+
+func inspectCSSScripts(cssTxt string) (scripts []Script) {
+	for _, loc := range reJavascriptURLinCSS.FindAllStringIndex(cssTxt, -1) {
+		scripts = append(scripts, Script{Category: JavascriptURL, Detail: shorten(cssTxt[loc[0]:])})
+	}
+	return
+}
+
+// scriptSource returns the attribute of a script element that links to an
+// external script ("" if the script is inline).
+func scriptSource(token html.Token) string {
+	for _, key := range []string{"src", "xlink:href", "href"} {
+		if v, ok := lookupAttr(token, key); ok {
+			return key + "=" + v
+		}
+	}
+	return ""
+}
+
+// isJavascriptURL reports whether an attribute's value is a javascript: URL.
+// Like browsers do, spaces and control characters are ignored.
+func isJavascriptURL(val string) bool {
+	u := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, val)
+
+	return strings.HasPrefix(strings.ToLower(u), "javascript:")
+}
+
+// shorten returns the first words of a text, spaces being collapsed.
+func shorten(txt string) string {
+	const maxLen = 60
+
+	txt = strings.Join(strings.Fields(txt), " ")
+	if r := []rune(txt); len(r) > maxLen {
+		return string(r[:maxLen]) + "…"
+	}
+	return txt
+}
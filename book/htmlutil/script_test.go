@@ -0,0 +1,84 @@
+package htmlutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInspectScripts(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want []ScriptCategory
+	}{
+		{
+			in: `<p class="foo">Hello <a href="chapter2.html">World</a></p><img src="cover.png" alt="javascript: the good parts"/>`,
+		},
+		{
+			in:   `<script type="text/javascript">alert('XSS')</script>`,
+			want: []ScriptCategory{InlineScript},
+		},
+		{
+			in:   `<script src="js/reader.js"></script>`,
+			want: []ScriptCategory{ExternalScript},
+		},
+		{
+			in:   `<body onload="init()"><p onclick="alert('XSS')">Hello</p></body>`,
+			want: []ScriptCategory{EventHandler, EventHandler},
+		},
+		{
+			in:   `<a href=" JaVa&#x09;Script:alert('XSS')">Hello</a>`,
+			want: []ScriptCategory{JavascriptURL},
+		},
+		{
+			in:   `<object data="javascript:alert('XSS')"></object><embed src="javascript:alert('XSS')"/>`,
+			want: []ScriptCategory{JavascriptURL, JavascriptURL},
+		},
+		{
+			in:   `<div style="background: url('javascript:alert(1)')">Hello</div>`,
+			want: []ScriptCategory{JavascriptURL},
+		},
+		{
+			in:   `<style>body { background: URL( javascript:alert(1) ) }</style>`,
+			want: []ScriptCategory{JavascriptURL},
+		},
+		{
+			in:   `<iframe srcdoc="&lt;script&gt;alert('XSS')&lt;/script&gt;"></iframe>`,
+			want: []ScriptCategory{InlineScript},
+		},
+		{
+			in:   `<svg onload="alert(1)"><script xlink:href="evil.js"/><a xlink:href="javascript:alert(1)"><text>Hello</text></a></svg><p onclick="alert(1)">Hello</p>`,
+			want: []ScriptCategory{ScriptedSVG, ScriptedSVG, ScriptedSVG, EventHandler},
+		},
+	}
+
+	for _, tc := range testCases {
+		scripts, err := InspectScripts(strings.NewReader(tc.in))
+		if err != nil {
+			t.Errorf("Fail to inspect scripts of '%s': %v", tc.in, err)
+			continue
+		}
+
+		var got []ScriptCategory
+		for _, s := range scripts {
+			got = append(got, s.Category)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("Inspecting scripts of '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.want, scripts)
+		}
+	}
+}
+
+func TestInspectCSSScripts(t *testing.T) {
+	in := `body { background: url(cover.png) } p { behavior: url("javascript:alert(1)") }`
+
+	scripts, err := InspectCSSScripts(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("Fail to inspect scripts of '%s': %v", in, err)
+	}
+
+	if len(scripts) != 1 || scripts[0].Category != JavascriptURL {
+		t.Errorf("Inspecting scripts of '%s' failed.\nWant: [%v]\nGot : %v", in, JavascriptURL, scripts)
+	}
+}
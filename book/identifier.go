@@ -0,0 +1,330 @@
+package book
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// List of schemes of Book's identifiers.
+const (
+	// IdentifierISBN is the International Standard Book Number.
+	IdentifierISBN = "isbn"
+	// IdentifierISSN is the International Standard Serial Number of the
+	// serial publication the book belongs to.
+	IdentifierISSN = "issn"
+	// IdentifierASIN is the Amazon Standard Identification Number.
+	IdentifierASIN = "asin"
+	// IdentifierDOI is the Digital Object Identifier.
+	IdentifierDOI = "doi"
+	// IdentifierOCLC is the OCLC's WorldCat control number.
+	IdentifierOCLC = "oclc"
+	// IdentifierLCCN is the Library of Congress Control Number.
+	IdentifierLCCN = "lccn"
+	// IdentifierGooglebooks is the Googlebooks' volume identifier.
+	IdentifierGooglebooks = "googlebooks"
+	// IdentifierOpenLibrary is the OpenLibrary's edition identifier.
+	IdentifierOpenLibrary = "openlibrary"
+	// IdentifierUUID is the Universally Unique Identifier of the book's file.
+	IdentifierUUID = "uuid"
+	// IdentifierCalibre is the identifier of the book in a Calibre library.
+	IdentifierCalibre = "calibre"
+)
+
+var (
+	// identifierSchemes maps usual names of identifiers' schemes (as found
+	// in EPUB's opf:scheme attribute for example) to Book's identifiers'
+	// scheme. EAN-13 identifiers are either ISBN or ISSN.
+	identifierSchemes = map[string]string{
+		"isbn": IdentifierISBN, "isbn10": IdentifierISBN, "isbn13": IdentifierISBN,
+		"isbn_10": IdentifierISBN, "isbn_13": IdentifierISBN,
+		"ean": IdentifierISBN, "ean13": IdentifierISBN, "ean-13": IdentifierISBN,
+		"issn": IdentifierISSN,
+		"asin": IdentifierASIN, "amazon": IdentifierASIN, "mobi-asin": IdentifierASIN,
+		"doi":  IdentifierDOI,
+		"oclc": IdentifierOCLC, "oclcnum": IdentifierOCLC,
+		"lccn":        IdentifierLCCN,
+		"google":      IdentifierGooglebooks,
+		"googlebooks": IdentifierGooglebooks,
+		"openlibrary": IdentifierOpenLibrary, "olid": IdentifierOpenLibrary,
+		"uuid":    IdentifierUUID,
+		"calibre": IdentifierCalibre,
+	}
+
+	// identifierPrefixes lists the usual prefixes (like URN or URL) of
+	// identifiers' values that give away their scheme.
+	identifierPrefixes = []struct {
+		prefix, scheme string
+	}{
+		{"urn:isbn:", IdentifierISBN}, {"isbn:", IdentifierISBN},
+		{"urn:issn:", IdentifierISSN}, {"issn:", IdentifierISSN},
+		{"urn:uuid:", IdentifierUUID}, {"uuid:", IdentifierUUID},
+		{"urn:doi:", IdentifierDOI}, {"doi:", IdentifierDOI},
+		{"https://doi.org/", IdentifierDOI}, {"http://doi.org/", IdentifierDOI},
+		{"https://dx.doi.org/", IdentifierDOI}, {"http://dx.doi.org/", IdentifierDOI},
+		{"urn:oclc:", IdentifierOCLC}, {"oclc:", IdentifierOCLC}, {"(ocolc)", IdentifierOCLC},
+		{"urn:lccn:", IdentifierLCCN}, {"lccn:", IdentifierLCCN},
+		{"https://lccn.loc.gov/", IdentifierLCCN},
+		{"amazon:", IdentifierASIN}, {"mobi-asin:", IdentifierASIN}, {"asin:", IdentifierASIN},
+		{"google:", IdentifierGooglebooks},
+		{"https://books.google.com/books?id=", IdentifierGooglebooks},
+		{"http://books.google.com/books?id=", IdentifierGooglebooks},
+		{"https://openlibrary.org/books/", IdentifierOpenLibrary},
+		{"calibre:", IdentifierCalibre},
+	}
+
+	reASIN        = regexp.MustCompile(`^[0-9A-Z]{10}$`)
+	reDOI         = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+	reOCLC        = regexp.MustCompile(`^(?:ocm|ocn|on)?(\d+)$`)
+	reLCCN        = regexp.MustCompile(`^([a-z]{0,3})(\d{2}|\d{4})-?(\d{1,6})$`)
+	reGooglebooks = regexp.MustCompile(`^[0-9A-Za-z_-]{12}$`)
+	reOpenLibrary = regexp.MustCompile(`^OL\d+[MWA]$`)
+	reUUID        = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// IdentifierSchemes lists the schemes of the identifiers supported by Book.
+func IdentifierSchemes() []string {
+	schemes := []string{}
+	for _, s := range identifierSchemes {
+		if !isInList(s, schemes) {
+			schemes = append(schemes, s)
+		}
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// SetIdentifier sets Book's identifier for the given scheme (like "asin" or
+// "doi") after normalizing it. ISBN are set using Book.SetISBN.
+// SetIdentifier reports non-recognized identifiers but do not fail.
+func (b *Book) SetIdentifier(scheme, value string) {
+	s, id, err := NormalizeIdentifier(scheme, value)
+	if err != nil {
+		b.ReportIssue("non-supported %s identifier (%s): %v", scheme, value, err)
+		return
+	}
+
+	if s == IdentifierISBN {
+		b.SetISBN(id)
+		return
+	}
+
+	if b.Identifiers == nil {
+		b.Identifiers = make(map[string]string)
+	}
+	b.Identifiers[s] = id
+}
+
+// Identifier returns Book's identifier for the given scheme or an empty
+// string if it is not known.
+func (b Book) Identifier(scheme string) string {
+	s := identifierSchemes[strings.ToLower(scheme)]
+	if s == IdentifierISBN {
+		return b.ISBN
+	}
+	return b.Identifiers[s]
+}
+
+// NormalizeIdentifier returns the normalized scheme and value of an
+// identifier. Check-digits are verified for identifiers that define one
+// (ISBN, ISSN or EAN-13).
+// If the scheme is not known or if the value is not valid for the scheme, an
+// error will be raised.
+func NormalizeIdentifier(scheme, value string) (string, string, error) {
+	s, known := identifierSchemes[strings.ToLower(strings.TrimSpace(scheme))]
+	if !known {
+		return "", "", fmt.Errorf("unknown identifier scheme '%s'", scheme)
+	}
+
+	id := trimIdentifierPrefix(strings.TrimSpace(value))
+	if id == "" {
+		return "", "", errors.New("empty identifier")
+	}
+
+	var err error
+	switch s {
+	case IdentifierISBN:
+		// EAN-13 starting with 977 are ISSN.
+		if clean := cleanISBN(id); len(clean) == 13 && clean[:3] == "977" {
+			s = IdentifierISSN
+			id, err = normalizeISSN(clean)
+			break
+		}
+		id, err = NormalizeISBN(id)
+
+	case IdentifierISSN:
+		id, err = normalizeISSN(id)
+
+	case IdentifierASIN:
+		if id = strings.ToUpper(id); !reASIN.MatchString(id) {
+			err = errors.New("invalid ASIN")
+		}
+
+	case IdentifierDOI:
+		if id = strings.ToLower(id); !reDOI.MatchString(id) {
+			err = errors.New("invalid DOI")
+		}
+
+	case IdentifierOCLC:
+		if m := reOCLC.FindStringSubmatch(strings.ToLower(id)); m != nil {
+			id = strings.TrimLeft(m[1], "0")
+		} else {
+			err = errors.New("invalid OCLC number")
+		}
+
+	case IdentifierLCCN:
+		id, err = normalizeLCCN(id)
+
+	case IdentifierGooglebooks:
+		if !reGooglebooks.MatchString(id) {
+			err = errors.New("invalid Googlebooks volume identifier")
+		}
+
+	case IdentifierOpenLibrary:
+		if id = strings.ToUpper(id); !reOpenLibrary.MatchString(id) {
+			err = errors.New("invalid OpenLibrary identifier")
+		}
+
+	case IdentifierUUID:
+		if id = strings.ToLower(strings.Trim(id, "{}")); !reUUID.MatchString(id) {
+			err = errors.New("invalid UUID")
+		}
+	}
+
+	if err != nil {
+		return "", "", err
+	}
+	return s, id, nil
+}
+
+// parseIdentifier guesses the scheme of an identifier from its declared
+// scheme or, if unknown, from the prefix of its value (like "urn:uuid:" or
+// "doi:"). It returns false if the scheme cannot be guessed.
+func parseIdentifier(scheme, value string) (string, string, bool) {
+	if s, known := identifierSchemes[strings.ToLower(strings.TrimSpace(scheme))]; known {
+		return s, value, true
+	}
+
+	v := strings.ToLower(strings.TrimSpace(value))
+	for _, p := range identifierPrefixes {
+		if strings.HasPrefix(v, p.prefix) {
+			return p.scheme, value, true
+		}
+	}
+
+	return "", "", false
+}
+
+// trimIdentifierPrefix removes the usual prefix of an identifier that gives
+// away its scheme (like "urn:uuid:").
+func trimIdentifierPrefix(value string) string {
+	v := strings.ToLower(value)
+	for _, p := range identifierPrefixes {
+		if strings.HasPrefix(v, p.prefix) {
+			return strings.TrimSpace(value[len(p.prefix):])
+		}
+	}
+	return value
+}
+
+// normalizeISSN returns an ISSN in its usual "0000-0000" format. ISSN can be
+// provided in their EAN-13 form (977 prefix). ISSN's (or EAN-13's)
+// check-digit is verified.
+func normalizeISSN(issn string) (string, error) {
+	clean := strings.ToUpper(cleanISBN(issn))
+
+	if len(clean) == 13 {
+		if clean[:3] != "977" {
+			return "", errors.New("invalid EAN-13 prefix for an ISSN")
+		}
+		if !isValidEAN13(clean) {
+			return "", errors.New("invalid EAN-13 check-digit")
+		}
+		clean = clean[3:10] + calcISSNcheckdigit(clean[3:10])
+	}
+
+	if len(clean) != 8 {
+		return "", errors.New("invalid ISSN length")
+	}
+
+	if strings.ContainsAny(clean[:7], "X") || calcISSNcheckdigit(clean[:7]) != clean[7:] {
+		return "", errors.New("invalid ISSN check-digit")
+	}
+
+	return clean[:4] + "-" + clean[4:], nil
+}
+
+// calcISSNcheckdigit calculates the check-digit of the first seven digits of
+// an ISSN.
+func calcISSNcheckdigit(digits string) string {
+	var sum int
+	for i, c := range digits[:7] {
+		sum += int(c-'0') * (8 - i)
+	}
+
+	switch digit := (11 - sum%11) % 11; digit {
+	case 10:
+		return "X"
+	default:
+		return string(rune('0' + digit))
+	}
+}
+
+// isValidEAN13 verifies the check-digit of an EAN-13 code.
+func isValidEAN13(ean string) bool {
+	if len(ean) != 13 {
+		return false
+	}
+
+	var sum int
+	for i, c := range ean {
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * (1 + 2*(i%2))
+	}
+
+	return sum%10 == 0
+}
+
+// normalizeLCCN normalizes a Library of Congress Control Number following
+// https://www.loc.gov/marc/lccn-namespace.html#normalization
+func normalizeLCCN(lccn string) (string, error) {
+	clean := strings.ToLower(strings.Join(strings.Fields(lccn), ""))
+	if i := strings.Index(clean, "/"); i >= 0 {
+		clean = clean[:i]
+	}
+
+	m := reLCCN.FindStringSubmatch(clean)
+	if m == nil {
+		return "", errors.New("invalid LCCN")
+	}
+
+	serial := m[3]
+	if strings.Contains(clean, "-") || len(serial) < 6 {
+		serial = strings.Repeat("0", 6-len(serial)) + serial
+	}
+
+	return m[1] + m[2] + serial, nil
+}
+
+// compareIdentifiers compares two sets of identifiers. Identifiers are the
+// same if they share at least one identifier's value for a scheme that
+// identifies a book. ISSN that identifies a serial, UUID that identifies a
+// file and Calibre identifier that is only meaningful within a given Calibre
+// library are ignored.
+// Identifiers are otherwise not comparable as different values can identify
+// different editions or files of the same book.
+func compareIdentifiers(ids1, ids2 map[string]string) SimilarityLevel {
+	for s, id1 := range ids1 {
+		if s == IdentifierISSN || s == IdentifierUUID || s == IdentifierCalibre {
+			continue
+		}
+		if id2, exists := ids2[s]; exists && id1 == id2 {
+			return AreTheSame
+		}
+	}
+	return AreNotComparable
+}
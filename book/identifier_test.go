@@ -0,0 +1,147 @@
+package book
+
+import (
+	"testing"
+)
+
+func TestNormalizeIdentifier(t *testing.T) {
+	testCases := []struct {
+		scheme, value string
+		outScheme     string
+		outValue      string
+		isErr         bool
+	}{
+		{scheme: "ISSN", value: "0378-5955", outScheme: "issn", outValue: "0378-5955"},
+		{scheme: "issn", value: "03178471", outScheme: "issn", outValue: "0317-8471"},
+		{scheme: "issn", value: "urn:issn:0317-8471", outScheme: "issn", outValue: "0317-8471"},
+		{scheme: "issn", value: "9770317847001", outScheme: "issn", outValue: "0317-8471"},
+		{scheme: "ean", value: "9770317847001", outScheme: "issn", outValue: "0317-8471"},
+		{scheme: "ean", value: "978-2-07-036822-8", outScheme: "isbn", outValue: "9782070368228"},
+		{scheme: "issn", value: "0317-8472", isErr: true},
+		{scheme: "issn", value: "9770317847002", isErr: true},
+		{scheme: "mobi-asin", value: "b00abcdefg", outScheme: "asin", outValue: "B00ABCDEFG"},
+		{scheme: "asin", value: "B00ABC", isErr: true},
+		{scheme: "doi", value: "https://doi.org/10.1000/XYZ123", outScheme: "doi", outValue: "10.1000/xyz123"},
+		{scheme: "doi", value: "11.1000/xyz123", isErr: true},
+		{scheme: "oclc", value: "(OCoLC)ocm00012345", outScheme: "oclc", outValue: "12345"},
+		{scheme: "lccn", value: "n78-89035", outScheme: "lccn", outValue: "n78089035"},
+		{scheme: "lccn", value: "2001-1114 /AC/r932", outScheme: "lccn", outValue: "2001001114"},
+		{scheme: "google", value: "Mj9UAAAAcAAJ", outScheme: "googlebooks", outValue: "Mj9UAAAAcAAJ"},
+		{scheme: "openlibrary", value: "ol7353617m", outScheme: "openlibrary", outValue: "OL7353617M"},
+		{scheme: "uuid", value: "urn:uuid:A1B2C3D4-0000-4000-8000-123456789ABC", outScheme: "uuid", outValue: "a1b2c3d4-0000-4000-8000-123456789abc"},
+		{scheme: "uuid", value: "not-a-uuid", isErr: true},
+		{scheme: "calibre", value: "1234", outScheme: "calibre", outValue: "1234"},
+		{scheme: "gutenberg", value: "11", isErr: true},
+	}
+
+	for _, tc := range testCases {
+		gotScheme, gotValue, err := NormalizeIdentifier(tc.scheme, tc.value)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("Normalizing %s:%s should fail", tc.scheme, tc.value)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Fail to normalize %s:%s: %v", tc.scheme, tc.value, err)
+			continue
+		}
+
+		if gotScheme != tc.outScheme || gotValue != tc.outValue {
+			t.Errorf("Normalizing %s:%s failed.\nWant: %s:%s\nGot : %s:%s", tc.scheme, tc.value, tc.outScheme, tc.outValue, gotScheme, gotValue)
+		}
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	testCases := []struct {
+		scheme, value string
+		want          string
+	}{
+		{scheme: "ISBN", value: "9782070368228", want: "isbn"},
+		{scheme: "", value: "urn:uuid:a1b2c3d4-0000-4000-8000-123456789abc", want: "uuid"},
+		{scheme: "URI", value: "doi:10.1000/xyz123", want: "doi"},
+		{scheme: "", value: "OCLC:12345", want: "oclc"},
+		{scheme: "URI", value: "http://www.gutenberg.org/11", want: ""},
+	}
+
+	for _, tc := range testCases {
+		got, _, _ := parseIdentifier(tc.scheme, tc.value)
+		if got != tc.want {
+			t.Errorf("Guessing scheme of %s:%s failed.\nWant: %v\nGot : %v", tc.scheme, tc.value, tc.want, got)
+		}
+	}
+}
+
+func TestSetIdentifierFromMap(t *testing.T) {
+	b, err := NewFromMap(map[string]string{
+		"Title":           "Alice",
+		"Identifier.asin": "b00abcdefg",
+		"Identifier.isbn": "2070368228",
+	})
+	if err != nil {
+		t.Fatalf("Fail to create Book from map: %v", err)
+	}
+
+	if got := b.Identifier("asin"); got != "B00ABCDEFG" {
+		t.Errorf("Setting ASIN failed.\nWant: B00ABCDEFG\nGot : %v", got)
+	}
+
+	if got := b.Identifier("isbn"); got != "9782070368228" {
+		t.Errorf("Setting ISBN failed.\nWant: 9782070368228\nGot : %v", got)
+	}
+
+	if _, err := NewFromMap(map[string]string{"Identifier.foo": "bar"}); err == nil {
+		t.Errorf("Setting an unknown identifier should fail")
+	}
+}
+
+func TestCompareIdentifierWith(t *testing.T) {
+	testCases := []struct {
+		b, b1 *Book
+		want  SimilarityLevel
+	}{
+		{
+			b:    &Book{Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			b1:   &Book{Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			want: AreTheSame,
+		},
+		{
+			b:    &Book{Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			b1:   &Book{Identifiers: map[string]string{"asin": "B00GFEDCBA"}},
+			want: AreNotComparable,
+		},
+		{
+			b:    &Book{ISBN: "9782070368228", Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			b1:   &Book{ISBN: "9782070360024", Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			want: AreAlmostTheSame,
+		},
+		{
+			b:    &Book{ISBN: "9782070368228", Identifiers: map[string]string{"uuid": "a1b2c3d4-0000-4000-8000-123456789abc"}},
+			b1:   &Book{ISBN: "9782070360024", Identifiers: map[string]string{"uuid": "a1b2c3d4-0000-4000-8000-123456789abc"}},
+			want: AreNotTheSame,
+		},
+		{
+			b:    &Book{Identifiers: map[string]string{"calibre": "42"}},
+			b1:   &Book{Identifiers: map[string]string{"calibre": "42"}},
+			want: AreNotComparable,
+		},
+		{
+			b:    &Book{Identifiers: map[string]string{"issn": "0317-8471"}},
+			b1:   &Book{Identifiers: map[string]string{"issn": "0317-8471"}},
+			want: AreNotComparable,
+		},
+		{
+			b:    &Book{ISBN: "9782070368228", Identifiers: map[string]string{"asin": "B00ABCDEFG"}},
+			b1:   &Book{ISBN: "9782070368228", Identifiers: map[string]string{"asin": "B00GFEDCBA"}},
+			want: AreTheSame,
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.b.compareIdentifierWith(tc.b1); got != tc.want {
+			t.Errorf("Comparing identifiers of %+v and %+v failed.\nWant: %v\nGot : %v", tc.b.Identifiers, tc.b1.Identifiers, tc.want, got)
+		}
+	}
+}
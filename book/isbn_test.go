@@ -106,3 +106,69 @@ func TestToISBN10(t *testing.T) {
 		}
 	}
 }
+
+func TestHyphenateISBN(t *testing.T) {
+	testCases := []struct {
+		in        string
+		want      string
+		wantGroup string
+		wantReg   string
+	}{
+		{"9782070368228", "978-2-07-036822-8", "French language", "978-2-07"},
+		{"2-07-036822-X", "2-07-036822-X", "French language", "978-2-07"},
+		{"0306406152", "0-306-40615-2", "English language", "978-0-306"},
+		{"978-0-306-40615-7", "978-0-306-40615-7", "English language", "978-0-306"},
+		{"9783161484100", "978-3-16-148410-0", "German language", "978-3-16"},
+		{"9791032705605", "979-10-327-0560-5", "France", "979-10-327"},
+		{"9788804668237", "978-88-04-66823-7", "Italy", "978-88-04"},
+	}
+
+	for _, tc := range testCases {
+		got, err := HyphenateISBN(tc.in)
+		if err != nil {
+			t.Errorf("Fail to hyphenate %s: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Hyphenate %s failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+
+		if got, _ := ISBNGroup(tc.in); got != tc.wantGroup {
+			t.Errorf("Registration group of %s failed.\nWant: %v\nGot : %v", tc.in, tc.wantGroup, got)
+		}
+
+		if got, _ := ISBNRegistrant(tc.in); got != tc.wantReg {
+			t.Errorf("Registrant of %s failed.\nWant: %v\nGot : %v", tc.in, tc.wantReg, got)
+		}
+	}
+
+	for _, in := range []string{"9782070368220", "978000", "9799999999999"} {
+		if got, err := HyphenateISBN(in); err == nil {
+			t.Errorf("Hyphenate %s should fail but got %s", in, got)
+		}
+	}
+}
+
+func TestCheckISBNLanguage(t *testing.T) {
+	testCases := []struct {
+		inISBN, inLang string
+		wantWarning    bool
+	}{
+		{"9782070368228", "fr", false},
+		{"9782070368228", "fr-CA", false},
+		{"9782070368228", "en", true},
+		{"9780306406157", "en", false},
+		{"9780306406157", "", false},
+		{"9784061598430", "ja", false},
+		{"9785170000005", "uk", false},
+		{"9785170000005", "ro", true},
+	}
+
+	for _, tc := range testCases {
+		b := &Book{ISBN: tc.inISBN, Language: tc.inLang, Report: NewReport()}
+		b.checkISBNLanguage()
+		if got := len(b.Report.Warnings) > 0; got != tc.wantWarning {
+			t.Errorf("Checking Language %s for ISBN %s failed.\nWant warning: %v\nGot : %v", tc.inLang, tc.inISBN, tc.wantWarning, b.Report.Warnings)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package book
+
+import (
+	"bytes"
+	"embed"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ./rangemessage/gen.go -o rangemessage/RangeMessage.xml
+
+var (
+	//go:embed rangemessage/RangeMessage.xml
+	rangeMessageDir embed.FS
+
+	// isbnRanges contains the ISBN ranges used to split ISBN into their
+	// elements. It defaults to the embedded RangeMessage data and can be
+	// replaced by the latest RangeMessage using LoadISBNRanges.
+	// Embedded RangeMessage is a simplified subset of the International ISBN
+	// Agency's one until it is refreshed using go generate.
+	isbnRanges = mustParseRangeMessage("rangemessage/RangeMessage.xml")
+
+	// isbnGroupLanguages lists the usual languages of books published in
+	// the ISBN registration groups that correspond to a language area or to
+	// a country with a main language.
+	isbnGroupLanguages = map[string][]string{
+		"978-0": {"en"}, "978-1": {"en"},
+		"978-2": {"fr"}, "979-10": {"fr"},
+		"978-3":  {"de"},
+		"978-4":  {"ja"},
+		"978-5":  {"ru", "uk", "be", "kk", "hy", "ka", "az", "uz", "ky", "tg", "tk"},
+		"978-7":  {"zh"},
+		"978-80": {"cs", "sk"},
+		"978-83": {"pl"},
+		"978-84": {"es", "ca", "eu", "gl"},
+		"978-85": {"pt"}, "978-972": {"pt"},
+		"978-88": {"it"}, "979-12": {"it"},
+		"978-90": {"nl", "fy"},
+		"978-91": {"sv"},
+		"979-11": {"ko"},
+	}
+)
+
+// ISBNRanges contains ISBN ranges as published by the International ISBN
+// Agency (RangeMessage) to split an ISBN into its elements (EAN prefix,
+// registration group, registrant and publication).
+type ISBNRanges struct {
+	// prefixes contains the rules to find the length of registration groups
+	// by EAN prefix.
+	prefixes map[string]isbnRangeGroup
+	// groups contains the rules to find the length of registrants by
+	// registration group (like "9782").
+	groups map[string]isbnRangeGroup
+}
+
+type isbnRangeGroup struct {
+	Prefix string
+	Agency string
+	Rules  []isbnRangeRule
+}
+
+type isbnRangeRule struct {
+	Start, End int
+	Length     int
+}
+
+// length returns the length of the ISBN element starting at digits
+// according to the group's rules.
+func (g isbnRangeGroup) length(digits string) (int, error) {
+	digits = (digits + "0000000")[:7]
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, r := range g.Rules {
+		if r.Start <= n && n <= r.End {
+			if r.Length == 0 {
+				return 0, fmt.Errorf("range %s is not defined in registration group %s", digits, g.Prefix)
+			}
+			return r.Length, nil
+		}
+	}
+
+	return 0, fmt.Errorf("range %s is not known in registration group %s", digits, g.Prefix)
+}
+
+// LoadISBNRanges reads ISBN ranges from an International ISBN Agency's
+// RangeMessage XML file (available at
+// https://www.isbn-international.org/range_file_generation) and uses them
+// instead of the embedded ones.
+func LoadISBNRanges(path string) error {
+	//#nosec G304 -- path is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ranges, err := parseRangeMessage(f)
+	if err != nil {
+		return fmt.Errorf("fail to read ISBN ranges from %s: %v", path, err)
+	}
+
+	isbnRanges = ranges
+	return nil
+}
+
+// HyphenateISBN returns an ISBN with hyphens separating its elements (like
+// "978-2-07-036822-8"). ISBN_10 are returned hyphenated as ISBN_10.
+func HyphenateISBN(isbn string) (string, error) {
+	parts, err := splitISBN(isbn)
+	if err != nil {
+		return "", err
+	}
+
+	if clean := cleanISBN(isbn); len(clean) == 10 {
+		parts = append(parts[1:len(parts)-1], strings.ToUpper(clean[9:]))
+	}
+
+	return strings.Join(parts, "-"), nil
+}
+
+// ISBNGroup returns the name of the language area or country of the
+// registration group of an ISBN (like "French language").
+func ISBNGroup(isbn string) (string, error) {
+	parts, err := splitISBN(isbn)
+	if err != nil {
+		return "", err
+	}
+
+	return isbnRanges.groups[parts[0]+parts[1]].Agency, nil
+}
+
+// ISBNRegistrant returns the hyphenated prefix of the registrant (usually the
+// publisher) of an ISBN (like "978-2-07").
+func ISBNRegistrant(isbn string) (string, error) {
+	parts, err := splitISBN(isbn)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(parts[:3], "-"), nil
+}
+
+// splitISBN splits an ISBN into its EAN prefix, registration group,
+// registrant, publication and check-digit elements.
+func splitISBN(isbn string) ([]string, error) {
+	isbn13, err := NormalizeISBN(isbn)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix, rest := isbn13[:3], isbn13[3:]
+	ean, known := isbnRanges.prefixes[prefix]
+	if !known {
+		return nil, fmt.Errorf("unknown EAN prefix %s", prefix)
+	}
+
+	gl, err := ean.length(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	group, known := isbnRanges.groups[prefix+rest[:gl]]
+	if !known {
+		return nil, fmt.Errorf("registration group %s-%s is not known", prefix, rest[:gl])
+	}
+
+	rl, err := group.length(rest[gl:])
+	if err != nil {
+		return nil, err
+	}
+
+	if gl+rl >= 9 {
+		return nil, fmt.Errorf("invalid registrant length for %s", isbn13)
+	}
+
+	return []string{prefix, rest[:gl], rest[gl : gl+rl], rest[gl+rl : 9], rest[9:]}, nil
+}
+
+// checkISBNLanguage verifies that Book's Language is consistent with the
+// registration group of its ISBN.
+func (b *Book) checkISBNLanguage() {
+	if b.ISBN == "" || b.Language == "" {
+		return
+	}
+
+	parts, err := splitISBN(b.ISBN)
+	if err != nil {
+		Debug.Printf("fail to find ISBN registration group of %s: %v", b.ISBN, err)
+		return
+	}
+
+	prefix := parts[0] + "-" + parts[1]
+	langs, known := isbnGroupLanguages[prefix]
+	if !known || isInList(BaseLanguage(b.Language), langs) {
+		return
+	}
+
+	b.ReportWarning("book Language (%s) is unusual for its ISBN registration group (%s: %s)", b.Language, prefix, isbnRanges.groups[parts[0]+parts[1]].Agency)
+}
+
+// rangeMessage is the XML structure of the RangeMessage published by the
+// International ISBN Agency.
+type rangeMessage struct {
+	Prefixes []rangeMessageGroup `xml:"EAN.UCCPrefixes>EAN.UCC"`
+	Groups   []rangeMessageGroup `xml:"RegistrationGroups>Group"`
+}
+
+type rangeMessageGroup struct {
+	Prefix string `xml:"Prefix"`
+	Agency string `xml:"Agency"`
+	Rules  []struct {
+		Range  string `xml:"Range"`
+		Length int    `xml:"Length"`
+	} `xml:"Rules>Rule"`
+}
+
+func parseRangeMessage(r io.Reader) (*ISBNRanges, error) {
+	var msg rangeMessage
+	if err := xml.NewDecoder(r).Decode(&msg); err != nil {
+		return nil, err
+	}
+
+	ranges := &ISBNRanges{
+		prefixes: make(map[string]isbnRangeGroup),
+		groups:   make(map[string]isbnRangeGroup),
+	}
+
+	for _, list := range []struct {
+		groups []rangeMessageGroup
+		dst    map[string]isbnRangeGroup
+	}{{msg.Prefixes, ranges.prefixes}, {msg.Groups, ranges.groups}} {
+		for _, g := range list.groups {
+			group := isbnRangeGroup{Prefix: g.Prefix, Agency: g.Agency}
+
+			for _, rule := range g.Rules {
+				bounds := strings.SplitN(rule.Range, "-", 2)
+				if len(bounds) != 2 {
+					return nil, fmt.Errorf("invalid range '%s' for %s", rule.Range, g.Prefix)
+				}
+
+				start, err := strconv.Atoi(bounds[0])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range '%s' for %s: %v", rule.Range, g.Prefix, err)
+				}
+
+				end, err := strconv.Atoi(bounds[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range '%s' for %s: %v", rule.Range, g.Prefix, err)
+				}
+
+				group.Rules = append(group.Rules, isbnRangeRule{Start: start, End: end, Length: rule.Length})
+			}
+
+			list.dst[strings.ReplaceAll(g.Prefix, "-", "")] = group
+		}
+	}
+
+	if len(ranges.prefixes) == 0 || len(ranges.groups) == 0 {
+		return nil, fmt.Errorf("no ISBN range found")
+	}
+
+	return ranges, nil
+}
+
+func mustParseRangeMessage(name string) *ISBNRanges {
+	data, err := rangeMessageDir.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	ranges, err := parseRangeMessage(bytes.NewReader(data))
+	if err != nil {
+		panic(err)
+	}
+	return ranges
+}
@@ -0,0 +1,236 @@
+// Package langdetect provides a naive content-based language identifier.
+//
+// Language identification relies on Cavnar and Trenkle's "N-Gram-Based Text
+// Categorization" approach: a text is classified by comparing the ranking of
+// its most frequent n-grams with the ones of reference language profiles.
+// Reference profiles are built from small text samples embedded in the
+// package.
+//
+// The script a text is written in is used as a pre-filter: languages
+// written with a dedicated script (Chinese, Japanese, Korean, Greek or
+// Hebrew) are identified from their script alone, texts written in a script
+// shared by several languages (Cyrillic or Arabic) are only compared with
+// the profiles of these languages, other texts are compared with the
+// profiles of languages written in the Latin script.
+package langdetect
+
+import (
+	"embed"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxNgram is the longest n-gram considered in profiles.
+	maxNgram = 4
+
+	// profileSize is the number of n-grams retained in a profile.
+	profileSize = 400
+
+	// MinTextLength is the minimal number of letters needed to try to
+	// identify a text's language.
+	MinTextLength = 100
+)
+
+var (
+	//go:embed samples/*.txt
+	samplesDir embed.FS
+
+	// profiles contains reference n-grams profiles by language.
+	profiles = mustLoadProfiles()
+
+	// scripts lists the scripts other than Latin together with the languages
+	// written with them. Languages of a script shared by several languages
+	// shall have a reference profile.
+	scripts = []struct {
+		langs  []string
+		tables []*unicode.RangeTable
+	}{
+		{[]string{"ja"}, []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana}},
+		{[]string{"ko"}, []*unicode.RangeTable{unicode.Hangul}},
+		{[]string{"zh"}, []*unicode.RangeTable{unicode.Han}},
+		{[]string{"ru", "uk", "bg"}, []*unicode.RangeTable{unicode.Cyrillic}},
+		{[]string{"el"}, []*unicode.RangeTable{unicode.Greek}},
+		{[]string{"ar", "fa"}, []*unicode.RangeTable{unicode.Arabic}},
+		{[]string{"he"}, []*unicode.RangeTable{unicode.Hebrew}},
+	}
+
+	// latinLangs lists the languages whose profile is not attached to a
+	// script, that is languages written in the Latin script.
+	latinLangs = listLatinLangs()
+)
+
+// profile represents the rank of the most frequent n-grams of a text.
+type profile map[string]int
+
+// Detect identifies the language of a text. It returns the two-letter ISO
+// 639-1 code of the language and a confidence score between 0 and 1.
+// If text is too short or if language cannot be identified, Detect returns an
+// empty language.
+func Detect(text string) (string, float64) {
+	var letters int
+	scriptCount := make([]int, len(scripts))
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+
+		for i, s := range scripts {
+			if unicode.In(r, s.tables...) {
+				scriptCount[i]++
+				break
+			}
+		}
+	}
+
+	if letters < MinTextLength {
+		return "", 0
+	}
+
+	// Japanese mixes Han with kana, so kana even in minority are a good
+	// indication of Japanese.
+	if scriptCount[0] > letters/10 {
+		return scripts[0].langs[0], float64(scriptCount[0]+scriptCount[2]) / float64(letters)
+	}
+
+	for i, s := range scripts {
+		if scriptCount[i] > letters/2 {
+			if len(s.langs) == 1 {
+				return s.langs[0], float64(scriptCount[i]) / float64(letters)
+			}
+			return classify(newProfile(text), s.langs)
+		}
+	}
+
+	return classify(newProfile(text), latinLangs)
+}
+
+// classify finds among candidates' reference profiles the one that is the
+// closest to p. It returns the corresponding language and a confidence score
+// based on the relative distance between the best and second best
+// candidates.
+func classify(p profile, candidates []string) (string, float64) {
+	bestLang, best, second := "", -1, -1
+	for _, lang := range candidates {
+		d := distance(p, profiles[lang])
+		switch {
+		case best < 0 || d < best:
+			bestLang, best, second = lang, d, best
+		case second < 0 || d < second:
+			second = d
+		}
+	}
+
+	if bestLang == "" || second <= 0 {
+		return bestLang, 0
+	}
+
+	return bestLang, float64(second-best) / float64(second)
+}
+
+// distance computes the "out-of-place" distance between two profiles.
+func distance(p, ref profile) int {
+	var d int
+	for ngram, rank := range p {
+		if refRank, ok := ref[ngram]; ok {
+			if rank > refRank {
+				d += rank - refRank
+			} else {
+				d += refRank - rank
+			}
+		} else {
+			d += profileSize
+		}
+	}
+	return d
+}
+
+// newProfile builds the n-grams profile of a text.
+func newProfile(text string) profile {
+	count := make(map[string]int)
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	for _, w := range words {
+		runes := []rune("_" + strings.Trim(w, "'") + "_")
+		for n := 1; n <= maxNgram; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				ngram := string(runes[i : i+n])
+				if ngram == "_" {
+					continue
+				}
+				count[ngram]++
+			}
+		}
+	}
+
+	ngrams := make([]string, 0, len(count))
+	for ngram := range count {
+		ngrams = append(ngrams, ngram)
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		if count[ngrams[i]] == count[ngrams[j]] {
+			return ngrams[i] < ngrams[j]
+		}
+		return count[ngrams[i]] > count[ngrams[j]]
+	})
+
+	if len(ngrams) > profileSize {
+		ngrams = ngrams[:profileSize]
+	}
+
+	p := make(profile, len(ngrams))
+	for rank, ngram := range ngrams {
+		p[ngram] = rank
+	}
+
+	return p
+}
+
+// listLatinLangs lists the languages whose reference profile is not attached
+// to a script.
+func listLatinLangs() []string {
+	nonLatin := make(map[string]bool)
+	for _, s := range scripts {
+		for _, lang := range s.langs {
+			nonLatin[lang] = true
+		}
+	}
+
+	var langs []string
+	for lang := range profiles {
+		if !nonLatin[lang] {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+
+	return langs
+}
+
+// mustLoadProfiles builds reference profiles from embedded samples. Samples
+// are named after the ISO 639-1 code of their language.
+func mustLoadProfiles() map[string]profile {
+	samples, err := samplesDir.ReadDir("samples")
+	if err != nil {
+		panic(err)
+	}
+
+	p := make(map[string]profile, len(samples))
+	for _, s := range samples {
+		txt, err := samplesDir.ReadFile(path.Join("samples", s.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		lang := strings.TrimSuffix(s.Name(), path.Ext(s.Name()))
+		p[lang] = newProfile(string(txt))
+	}
+
+	return p
+}
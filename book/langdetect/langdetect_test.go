@@ -0,0 +1,33 @@
+package langdetect
+
+import (
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"The quick brown fox jumps over the lazy dog. In the beginning God created the heaven and the earth. And the earth was without form, and void; and darkness was upon the face of the deep. And the Spirit of God moved upon the face of the waters.", "en"},
+		{"Au commencement, Dieu créa les cieux et la terre. La terre était informe et vide: il y avait des ténèbres à la surface de l'abîme, et l'esprit de Dieu se mouvait au-dessus des eaux. Dieu dit: Que la lumière soit! Et la lumière fut.", "fr"},
+		{"Im Anfang schuf Gott Himmel und Erde. Und die Erde war wüst und leer, und es war finster auf der Tiefe; und der Geist Gottes schwebte auf dem Wasser. Und Gott sprach: Es werde Licht! und es ward Licht.", "de"},
+		{"En el principio creó Dios los cielos y la tierra. Y la tierra estaba desordenada y vacía, y las tinieblas estaban sobre la faz del abismo, y el Espíritu de Dios se movía sobre la faz de las aguas. Y dijo Dios: Sea la luz; y fue la luz.", "es"},
+		{"In principio Dio creò il cielo e la terra. Ora la terra era informe e deserta e le tenebre ricoprivano l'abisso e lo spirito di Dio aleggiava sulle acque. Dio disse: Sia la luce! E la luce fu. Dio vide che la luce era cosa buona.", "it"},
+		{"No princípio criou Deus os céus e a terra. E a terra era sem forma e vazia; e havia trevas sobre a face do abismo; e o Espírito de Deus se movia sobre a face das águas. E disse Deus: Haja luz; e houve luz.", "pt"},
+		{"In het begin schiep God de hemel en de aarde. De aarde nu was woest en leeg, en duisternis lag op de watervloed, en de Geest van God zweefde boven het water. En God zei: Laat er licht zijn! En er was licht.", "nl"},
+		{"道可道，非常道。名可名，非常名。無名天地之始；有名萬物之母。故常無欲，以觀其妙；常有欲，以觀其徼。此兩者，同出而異名，同謂之玄。玄之又玄，衆妙之門。天下皆知美之為美，斯惡已。皆知善之為善，斯不善已。故有無相生，難易相成，長短相較，高下相傾，音聲相和，前後相隨。是以聖人處無為之事，行不言之教；萬物作焉而不辭，生而不有，為而不恃，功成而弗居。夫唯弗居，是以不去。不尚賢，使民不爭；不貴難得之貨，使民不為盜；不見可欲，使民心不亂。是以聖人之治，虛其心，實其腹，弱其志，強其骨。常使民無知無欲。使夫智者不敢為也。為無為，則無不治。", "zh"},
+		{"В начале сотворил Бог небо и землю. Земля же была безвидна и пуста, и тьма над бездною, и Дух Божий носился над водою. И сказал Бог: да будет свет. И стал свет. И увидел Бог свет, что он хорош, и отделил Бог свет от тьмы.", "ru"},
+		{"На початку створив Бог небо та землю. А земля була пуста та порожня, і темрява була над безоднею, а Дух Божий ширяв над поверхнею води. І сказав Бог: Нехай станеться світло! І сталося світло.", "uk"},
+		{"В началото Бог сътвори небето и земята. А земята беше пуста и празна; и тъмнина покриваше бездната; и Божият Дух се носеше над водата. И Бог каза: Да бъде светлина. И стана светлина.", "bg"},
+		{"في البدء خلق الله السماوات والأرض. وكانت الأرض خربة وخالية، وعلى وجه الغمر ظلمة، وروح الله يرف على وجه المياه. وقال الله: ليكن نور، فكان نور. ورأى الله النور أنه حسن.", "ar"},
+		{"در ابتدا، خدا آسمانها و زمین را آفرید. و زمین تهی و بایر بود و تاریکی بر روی لجه. و روح خدا سطح آبها را فرو گرفت. و خدا گفت: روشنایی بشود، و روشنایی شد. و خدا روشنایی را دید که نیکوست.", "fa"},
+		{"Too short to say.", ""},
+	}
+
+	for _, tc := range testCases {
+		if got, _ := Detect(tc.in); got != tc.want {
+			t.Errorf("Detect language of '%.30s...' failed.\nWant: %s\nGot : %s", tc.in, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package book
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+var (
+	// languageNames maps normalized language names (in English, in the
+	// language itself or in French, German, Spanish or Italian) to their
+	// language tag.
+	languageNames map[string]language.Tag
+
+	// languageNamers lists the languages, besides English and the language
+	// itself, in which language names are looked-up (like 'anglais' or
+	// 'inglés').
+	languageNamers = []display.Namer{
+		display.Languages(language.French),
+		display.Languages(language.German),
+		display.Languages(language.Spanish),
+		display.Languages(language.Italian),
+	}
+
+	// languageNamesOnce ensures that languageNames is only populated once.
+	languageNamesOnce sync.Once
+)
+
+// NormalizeLanguage returns the BCP 47 tag of a language.
+// lang can be an ISO 639-1, ISO 639-2 or ISO 639-3 code, a BCP 47 tag (like
+// 'pt-BR' or 'zh-Hant') or a language's name either in English, in the
+// language itself or in French, German, Spanish or Italian (like 'French',
+// 'français' or 'francés').
+// Script and region are kept only if they are explicitly specified.
+// Undetermined language ('und' or 'un') is normalized to an empty string.
+// If lang cannot be recognized, an error is raised.
+func NormalizeLanguage(lang string) (string, error) {
+	lang = strings.TrimSpace(lang)
+	// For some reason several of my ebook report "UN" as Language
+	if lang == "" || strings.EqualFold(lang, "un") || strings.EqualFold(lang, "und") {
+		return "", nil
+	}
+
+	tag, err := language.Parse(lang)
+	if err != nil {
+		var found bool
+		if tag, found = lookupLanguageName(lang); !found {
+			return "", errors.New("unknown language")
+		}
+	}
+
+	base, conf := tag.Base()
+	if conf != language.Exact {
+		return "", errors.New("unknown language")
+	}
+
+	parts := []interface{}{base}
+	if script, conf := tag.Script(); conf == language.Exact {
+		parts = append(parts, script)
+	}
+	if region, conf := tag.Region(); conf == language.Exact {
+		parts = append(parts, region)
+	}
+
+	norm, err := language.Compose(parts...)
+	if err != nil {
+		return "", err
+	}
+
+	return norm.String(), nil
+}
+
+// BaseLanguage returns the ISO 639-1 (or ISO 639-3 if no ISO 639-1 exists)
+// code of a language tag without script or region (e.g. 'pt' for 'pt-BR').
+// BaseLanguage returns lang as-is if it cannot be recognized.
+func BaseLanguage(lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return lang
+	}
+
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// LanguageName returns the name of a language expressed in the language of
+// locale. If locale is 'self', the name is expressed in the language itself.
+// LanguageName returns lang as-is if lang or locale cannot be recognized.
+func LanguageName(locale string, lang string) string {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return lang
+	}
+
+	var namer display.Namer
+	if locale == "self" {
+		namer = display.Self
+	} else {
+		loc, err := language.Parse(locale)
+		if err != nil {
+			return lang
+		}
+		if namer = display.Languages(loc); namer == nil {
+			return lang
+		}
+	}
+
+	if name := namer.Name(tag); name != "" {
+		return name
+	}
+	return lang
+}
+
+// sameLanguage checks whether two languages tags share the same base
+// language.
+func sameLanguage(lang1, lang2 string) bool {
+	return strings.EqualFold(BaseLanguage(lang1), BaseLanguage(lang2))
+}
+
+// lookupLanguageName retrieves a language tag from its name.
+func lookupLanguageName(name string) (language.Tag, bool) {
+	languageNamesOnce.Do(func() {
+		languageNames = make(map[string]language.Tag)
+		addName := func(n string, tag language.Tag) {
+			if n = strings.TrimSpace(NormalizeString(n)); n != "" {
+				if _, exists := languageNames[n]; !exists {
+					languageNames[n] = tag
+				}
+			}
+		}
+
+		// Names in English or in the language itself take precedence over
+		// names in other languages.
+		for _, tag := range display.Supported.Tags() {
+			addName(display.English.Languages().Name(tag), tag)
+			addName(display.Self.Name(tag), tag)
+		}
+		for _, namer := range languageNamers {
+			for _, tag := range display.Supported.Tags() {
+				addName(namer.Name(tag), tag)
+			}
+		}
+	})
+
+	tag, found := languageNames[strings.TrimSpace(NormalizeString(name))]
+	return tag, found
+}
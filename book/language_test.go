@@ -0,0 +1,61 @@
+package book
+
+import (
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{"fr", "fr", false},
+		{"FR", "fr", false},
+		{"fre", "fr", false},
+		{"fra", "fr", false},
+		{"French", "fr", false},
+		{"français", "fr", false},
+		{"Deutsch", "de", false},
+		{"pt-BR", "pt-BR", false},
+		{"pt_br", "pt-BR", false},
+		{"zh-Hant", "zh-Hant", false},
+		{"en_US", "en-US", false},
+		{"UN", "", false},
+		{"und", "", false},
+		{"", "", false},
+		{"klingonese", "", true},
+	}
+
+	for _, tc := range testCases {
+		got, err := NormalizeLanguage(tc.in)
+		if (err != nil) != tc.err {
+			t.Errorf("Normalize language %#v failed:\nWant error: %v\nGot : %v", tc.in, tc.err, err)
+			continue
+		}
+
+		if got != tc.out {
+			t.Errorf("Normalize language %#v failed.\nWant: %v\nGot : %v", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestLanguageName(t *testing.T) {
+	testCases := []struct {
+		inLocale string
+		inLang   string
+		out      string
+	}{
+		{"en", "fr", "French"},
+		{"fr", "en", "anglais"},
+		{"self", "fr", "français"},
+		{"en", "pt-BR", "Brazilian Portuguese"},
+		{"en", "not a language", "not a language"},
+	}
+
+	for _, tc := range testCases {
+		if got := LanguageName(tc.inLocale, tc.inLang); got != tc.out {
+			t.Errorf("Language name of %#v in %#v failed.\nWant: %v\nGot : %v", tc.inLang, tc.inLocale, tc.out, got)
+		}
+	}
+}
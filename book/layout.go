@@ -0,0 +1,82 @@
+package book
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reLayoutField captures the '{Attribute}' placeholders of a directory
+	// layout.
+	reLayoutField = regexp.MustCompile(`\{(\w+)\}`)
+
+	// dirLayouts is a collection of regexp built from user-defined directory
+	// layouts to extract information from a Book's path.
+	// There is no built-in layout: a layout matches any path whose last
+	// elements look like it (for example a download folder could be taken
+	// for a Series), so that it is only meaningful for folders organised
+	// accordingly.
+	// Reminder: order is important as only first match is considered.
+	dirLayouts []*regexp.Regexp
+)
+
+// NewFromDirLayout creates a Book whose information are guessed from its
+// path, interpreting its parent directories according to known directory
+// layouts. Without user-defined layouts (see AddDirLayout), nothing is
+// guessed.
+func NewFromDirLayout(path string) (*Book, error) {
+	return guess(filepath.ToSlash(path), dirLayouts...)
+}
+
+// AddDirLayout adds a new directory layout to guess Book's information from
+// its path. Layouts are tried in the order they are added.
+//
+// A layout describes the last elements of a Book's path (without the file's
+// extension) using '/' as separator and '{Attribute}' placeholders whose
+// names shall correspond to an attribute known by NewFromMap, for example:
+// "{Authors}/{Series}/{SeriesIndex} - {Title}".
+func AddDirLayout(layout string) error {
+	re, err := compileLayout(layout)
+	if err != nil {
+		return fmt.Errorf("invalid layout '%s': %v", layout, err)
+	}
+
+	dirLayouts = append(dirLayouts, re)
+	return nil
+}
+
+// compileLayout converts a directory layout into a regexp.
+func compileLayout(layout string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString(`^(?:.*/)?`)
+
+	var hasField bool
+	var last int
+	for _, loc := range reLayoutField.FindAllStringSubmatchIndex(layout, -1) {
+		expr.WriteString(regexp.QuoteMeta(layout[last:loc[0]]))
+		last = loc[1]
+
+		name := layout[loc[2]:loc[3]]
+		if !isMapAttribute(name) {
+			return nil, fmt.Errorf("placeholder '%s' is not a known Book's attribute", name)
+		}
+		hasField = true
+
+		switch name = strings.Title(name); name {
+		case "SeriesIndex":
+			expr.WriteString(reSeriesIndex)
+		default:
+			expr.WriteString(`(?P<` + name + `>[^/]+?)`)
+		}
+	}
+	expr.WriteString(regexp.QuoteMeta(layout[last:]))
+	expr.WriteString(reExt)
+
+	if !hasField {
+		return nil, fmt.Errorf("no placeholder found")
+	}
+
+	return regexp.Compile(expr.String())
+}
@@ -0,0 +1,74 @@
+package book
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDirLayout(t *testing.T) {
+	testCases := []struct {
+		inLayout string
+		inPath   string
+		out      map[string]string
+	}{
+		{
+			"{Authors}/{Series}/{SeriesIndex} - {Title}",
+			"/books/incoming/Pierre Pelot/La compagnie des glaces/03 - Sun Company.epub",
+			map[string]string{"Authors": "Pierre Pelot", "Series": "La compagnie des glaces", "SeriesIndex": "03", "Title": "Sun Company"},
+		},
+		{
+			"{Authors}/{Series}/{SeriesIndex} - {Title}",
+			"Pierre Pelot/La compagnie des glaces/T03 - Sun Company.epub",
+			map[string]string{"Authors": "Pierre Pelot", "Series": "La compagnie des glaces", "SeriesIndex": "03", "Title": "Sun Company"},
+		},
+		{
+			"{Authors}/{Series}/{SeriesIndex} - {Title}",
+			"/books/incoming/Sun Company.epub",
+			nil,
+		},
+		{
+			"{Publisher}/{Authors}/{Title} ({Language})",
+			"/books/Gallimard/Jules Verne/Cinq semaines en ballon (fr).epub",
+			map[string]string{"Publisher": "Gallimard", "Authors": "Jules Verne", "Title": "Cinq semaines en ballon", "Language": "fr"},
+		},
+	}
+
+	for _, tc := range testCases {
+		re, err := compileLayout(tc.inLayout)
+		if err != nil {
+			t.Fatalf("fail to compile layout %s: %v", tc.inLayout, err)
+		}
+
+		if got := reFindStringSubmatchAsMap(tc.inPath, re); fmt.Sprint(got) != fmt.Sprint(tc.out) {
+			t.Errorf("Guessing %#v using %#v failed:\nWant: %#v\nGot : %#v", tc.inPath, tc.inLayout, tc.out, got)
+		}
+	}
+}
+
+func TestAddDirLayout(t *testing.T) {
+	testCases := []struct {
+		in  string
+		err bool
+	}{
+		{"{Authors}/{Title}", false},
+		{"{authors}/{Series} {SeriesIndex}/{Title}", false},
+		{"{Authors}/{Translator}/{Title}", true},
+		{"incoming/book", true},
+	}
+
+	defer func() { dirLayouts = nil }()
+	for _, tc := range testCases {
+		if err := AddDirLayout(tc.in); (err != nil) != tc.err {
+			t.Errorf("Adding layout %#v failed:\nWant error: %v\nGot : %v", tc.in, tc.err, err)
+		}
+	}
+
+	got, err := NewFromDirLayout("/books/Pierre Pelot/Sun Company.epub")
+	if err != nil {
+		t.Fatalf("fail to guess from directory layout: %v", err)
+	}
+
+	if got == nil || got.Title != "Sun Company" || len(got.Authors) != 1 || got.Authors[0] != "Pierre Pelot" {
+		t.Errorf("Guessing from user-defined directory layout failed.\nWant: Sun Company by Pierre Pelot\nGot : %+v", got)
+	}
+}
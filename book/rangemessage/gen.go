@@ -0,0 +1,67 @@
+//go:build ignore
+
+// gen downloads the ISBN ranges published by the International ISBN Agency
+// (RangeMessage) so that they can be embedded in libro.
+//
+// Usage:
+//
+//	go run gen.go [-o RangeMessage.xml]
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+const rangeMessageURL = "https://www.isbn-international.org/export_rangemessage.xml"
+
+func main() {
+	out := flag.String("o", "RangeMessage.xml", "file to write the RangeMessage to")
+	flag.Parse()
+
+	if err := download(*out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// download fetches the RangeMessage and writes it to path once it is
+// verified to be a RangeMessage.
+func download(path string) error {
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	resp, err := client.Get(rangeMessageURL)
+	if err != nil {
+		return fmt.Errorf("fail to download ISBN ranges: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fail to download ISBN ranges: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("fail to download ISBN ranges: %v", err)
+	}
+
+	var msg struct {
+		XMLName xml.Name `xml:"ISBNRangeMessage"`
+		Groups  []struct {
+			Prefix string `xml:"Prefix"`
+		} `xml:"RegistrationGroups>Group"`
+	}
+	if err := xml.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("fail to read downloaded ISBN ranges: %v", err)
+	}
+	if len(msg.Groups) == 0 {
+		return fmt.Errorf("fail to read downloaded ISBN ranges: no registration group found")
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
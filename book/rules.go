@@ -0,0 +1,162 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// List of targets a Rule can be applied to.
+const (
+	// TargetPath applies a Rule to Book's file path.
+	TargetPath = "path"
+	// TargetTitle applies a Rule to Book's Title.
+	TargetTitle = "title"
+	// TargetSubTitle applies a Rule to Book's SubTitle.
+	TargetSubTitle = "subtitle"
+	// TargetContent applies a Rule to Book's textual content.
+	TargetContent = "content"
+)
+
+var (
+	// userGuessers contains user-defined guessers by target.
+	userGuessers = make(map[string][]*regexp.Regexp)
+
+	// userCleaners contains user-defined cleaners by target.
+	userCleaners = make(map[string][]*regexp.Regexp)
+)
+
+// Rule is a user-defined guesser or cleaner.
+// Rule's Regexp captures Book's attributes using named capturing groups whose
+// name shall correspond to an attribute known by NewFromMap.
+type Rule struct {
+	// Target is the Book's information the Rule is applied to. It is one of
+	// "path", "title", "subtitle" or "content".
+	Target string `json:"target"`
+
+	// Cleaner, if set, replaces Book's attributes by the captured values
+	// instead of only completing missing ones. Cleaners are only supported
+	// for "title" and "subtitle" targets.
+	Cleaner bool `json:"cleaner,omitempty"`
+
+	// Regexp is the regular expression capturing Book's attributes.
+	Regexp string `json:"regexp"`
+}
+
+// LoadRules reads an ordered list of Rules from a JSON file and adds them to
+// the guessers and cleaners used by Book. User-defined Rules take precedence
+// over built-in ones.
+func LoadRules(path string) error {
+	//#nosec G304 -- path is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var rules []Rule
+	if err := json.NewDecoder(f).Decode(&rules); err != nil {
+		return fmt.Errorf("fail to decode rules from %s: %v", path, err)
+	}
+
+	return AddRules(rules...)
+}
+
+// AddRules adds Rules to the guessers and cleaners used by Book. User-defined
+// Rules take precedence over built-in ones and are run in the given order.
+// AddRules fails without adding any Rule if one of them is not valid.
+func AddRules(rules ...Rule) error {
+	guessers, cleaners := make(map[string][]*regexp.Regexp), make(map[string][]*regexp.Regexp)
+
+	for i, rule := range rules {
+		re, err := rule.compile()
+		if err != nil {
+			return fmt.Errorf("invalid rule #%d: %v", i+1, err)
+		}
+
+		if rule.Cleaner {
+			cleaners[rule.Target] = append(cleaners[rule.Target], re)
+		} else {
+			guessers[rule.Target] = append(guessers[rule.Target], re)
+		}
+	}
+
+	for target, re := range guessers {
+		userGuessers[target] = append(userGuessers[target], re...)
+	}
+
+	for target, re := range cleaners {
+		userCleaners[target] = append(userCleaners[target], re...)
+	}
+
+	return nil
+}
+
+// compile verifies that Rule is valid and compiles its Regexp.
+func (rule Rule) compile() (*regexp.Regexp, error) {
+	switch rule.Target {
+	case TargetPath, TargetContent:
+		if rule.Cleaner {
+			return nil, fmt.Errorf("cleaner is not supported for target '%s'", rule.Target)
+		}
+
+	case TargetTitle, TargetSubTitle:
+
+	default:
+		return nil, fmt.Errorf("unknown target '%s'", rule.Target)
+	}
+
+	re, err := regexp.Compile(rule.Regexp)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasGroup bool
+	for _, name := range re.SubexpNames()[1:] {
+		if name == "" {
+			return nil, fmt.Errorf("unnamed capturing group in '%s' (use '(?:...)' for non-capturing groups)", rule.Regexp)
+		}
+
+		if !isMapAttribute(name) {
+			return nil, fmt.Errorf("capturing group '%s' is not a known Book's attribute", name)
+		}
+		hasGroup = true
+	}
+
+	if !hasGroup {
+		return nil, fmt.Errorf("no capturing group in '%s'", rule.Regexp)
+	}
+
+	return re, nil
+}
+
+// withUserGuessers returns the guessers to apply to target, user-defined
+// ones first.
+func withUserGuessers(target string, builtins ...*regexp.Regexp) []*regexp.Regexp {
+	return withUserRules(userGuessers[target], builtins)
+}
+
+// withUserCleaners returns the cleaners to apply to target, user-defined
+// ones first.
+func withUserCleaners(target string, builtins ...*regexp.Regexp) []*regexp.Regexp {
+	return withUserRules(userCleaners[target], builtins)
+}
+
+func withUserRules(user, builtins []*regexp.Regexp) []*regexp.Regexp {
+	rules := make([]*regexp.Regexp, 0, len(user)+len(builtins))
+	rules = append(rules, user...)
+	return append(rules, builtins...)
+}
+
+// isMapAttribute checks whether an attribute's name is supported by
+// NewFromMap.
+func isMapAttribute(name string) bool {
+	for _, attr := range mapAttributes {
+		if strings.Title(name) == attr {
+			return true
+		}
+	}
+	return false
+}
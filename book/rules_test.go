@@ -0,0 +1,83 @@
+package book
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/pirmd/verify"
+)
+
+func TestAddRules(t *testing.T) {
+	testCases := []struct {
+		in  Rule
+		err bool
+	}{
+		{Rule{Target: TargetPath, Regexp: `^(?P<Authors>.+) - (?P<Title>.+)\.epub$`}, false},
+		{Rule{Target: TargetTitle, Regexp: `^(?P<title>.+) \(roman\)$`, Cleaner: true}, false},
+		{Rule{Target: TargetSubTitle, Regexp: `^(?P<Series>.+) (?P<SeriesIndex>\d+)$`}, false},
+		{Rule{Target: TargetContent, Regexp: `Traduit par (?P<Publisher>.+)`}, false},
+		{Rule{Target: "filename", Regexp: `^(?P<Title>.+)$`}, true},
+		{Rule{Target: TargetPath, Regexp: `^(?P<Title>.+)$`, Cleaner: true}, true},
+		{Rule{Target: TargetTitle, Regexp: `^(?P<Title>.+$`}, true},
+		{Rule{Target: TargetTitle, Regexp: `^(?P<Translator>.+)$`}, true},
+		{Rule{Target: TargetTitle, Regexp: `^(.+) - (?P<Title>.+)$`}, true},
+		{Rule{Target: TargetTitle, Regexp: `^.+$`}, true},
+	}
+
+	defer resetRules()
+	for _, tc := range testCases {
+		resetRules()
+		if err := AddRules(tc.in); (err != nil) != tc.err {
+			t.Errorf("Adding rule %#v failed:\nWant error: %v\nGot : %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	Verbose, Debug = verify.NewLogger(t), verify.NewLogger(t)
+
+	defer resetRules()
+	if err := LoadRules("testdata/rules/rules.json"); err != nil {
+		t.Fatalf("fail to load rules: %v", err)
+	}
+
+	t.Run("PathRulePrecedence", func(t *testing.T) {
+		got, err := NewFromFilename("/books/Sun Company - Pierre Pelot.epub")
+		if err != nil {
+			t.Fatalf("fail to guess from filename: %v", err)
+		}
+
+		want := &Book{Title: "Sun Company", Authors: []string{"Pierre Pelot"}, Report: NewReport()}
+		if failure := verify.Equal(want, got); failure != nil {
+			t.Errorf("Guessing from filename failed:\n%v", failure)
+		}
+	})
+
+	t.Run("TitleGuesser", func(t *testing.T) {
+		got := &Book{Title: "La compagnie des glaces #25", Report: NewReport()}
+		if err := got.GuessFromMetadata(); err != nil {
+			t.Fatalf("fail to guess from metadata: %v", err)
+		}
+
+		want := &Book{Title: "La compagnie des glaces #25", Series: "La compagnie des glaces", SeriesIndex: 25, Report: NewReport()}
+		if failure := verify.Equal(want, got); failure != nil {
+			t.Errorf("Guessing from Title failed:\n%v", failure)
+		}
+	})
+
+	t.Run("TitleCleaner", func(t *testing.T) {
+		got := &Book{Title: "Sun Company (roman)", Report: NewReport()}
+		if err := got.CleanMetadata(); err != nil {
+			t.Fatalf("fail to clean metadata: %v", err)
+		}
+
+		if want := "Sun Company"; got.Title != want {
+			t.Errorf("Cleaning Title failed.\nWant: %v\nGot : %v", want, got.Title)
+		}
+	})
+}
+
+func resetRules() {
+	userGuessers = make(map[string][]*regexp.Regexp)
+	userCleaners = make(map[string][]*regexp.Regexp)
+}
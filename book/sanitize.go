@@ -0,0 +1,145 @@
+package book
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reManifestItem matches an OPF's manifest item.
+	reManifestItem = regexp.MustCompile(`<item\b[^>]*>`)
+
+	// reItemProperties matches the properties attribute of an OPF's manifest
+	// item.
+	reItemProperties = regexp.MustCompile(`\s+properties\s*=\s*("[^"]*"|'[^']*')`)
+
+	// reItemHref matches the href attribute of an OPF's manifest item.
+	reItemHref = regexp.MustCompile(`\bhref\s*=\s*("[^"]*"|'[^']*')`)
+)
+
+// SanitizeContent writes to w a copy of Book's EPUB where HTML, SVG and CSS
+// resources are cleaned from the content that CheckContentSecurity would
+// report as a security risk (see htmlutil.Scanner.Sanitize). As scripts are
+// removed, manifest's items are not declared as 'scripted' anymore. Other
+// resources are copied untouched.
+// SanitizeContent returns a list of messages describing what was removed,
+// prefixed by the name of the modified resource.
+func (b *Book) SanitizeContent(w io.Writer) ([]string, error) {
+	r, err := zip.OpenReader(b.Path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open EPUB: %v", err)
+	}
+	defer r.Close()
+
+	EPUBScanner := newEPUBScanner()
+
+	var report []string
+	zw := zip.NewWriter(w)
+	for _, f := range r.File {
+		var sanitize func(io.Reader, io.Writer) ([]string, error)
+		switch {
+		case isHTMLResource(f.Name), isSVGResource(f.Name):
+			// SVG resources are made of SVG content that is sanitized
+			// according to foreign content rules.
+			sanitize = EPUBScanner.Sanitize
+		case isCSSResource(f.Name):
+			sanitize = EPUBScanner.SanitizeCSS
+		case isOPFResource(f.Name):
+			sanitize = sanitizeScriptedProperties
+		default:
+			// Copy keeps resources untouched, notably the 'mimetype' file
+			// that should stay first and uncompressed.
+			if err := zw.Copy(f); err != nil {
+				return report, fmt.Errorf("fail to copy '%s': %v", f.Name, err)
+			}
+			continue
+		}
+
+		Debug.Printf("sanitize resource: %s", f.Name)
+		removed, err := sanitizeZipFile(zw, f, sanitize)
+		if err != nil {
+			return report, fmt.Errorf("fail to sanitize '%s': %v", f.Name, err)
+		}
+
+		for _, msg := range removed {
+			Verbose.Printf("%s: %s", f.Name, msg)
+			report = append(report, fmt.Sprintf("%s: %s", f.Name, msg))
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// sanitizeZipFile writes to zw a sanitized version of a zip entry.
+func sanitizeZipFile(zw *zip.Writer, f *zip.File, sanitize func(io.Reader, io.Writer) ([]string, error)) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	hdr := f.FileHeader
+	fw, err := zw.CreateHeader(&hdr)
+	if err != nil {
+		return nil, err
+	}
+
+	return sanitize(rc, fw)
+}
+
+// sanitizeScriptedProperties removes 'scripted' from the properties of an
+// OPF's manifest items. Properties attribute is removed if 'scripted' was its
+// only value.
+func sanitizeScriptedProperties(r io.Reader, w io.Writer) ([]string, error) {
+	opf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var report []string
+	sanitized := reManifestItem.ReplaceAllStringFunc(string(opf), func(item string) string {
+		m := reItemProperties.FindStringSubmatchIndex(item)
+		if m == nil {
+			return item
+		}
+
+		quote, value := item[m[2]:m[2]+1], item[m[2]+1:m[3]-1]
+		if !isInProperties("scripted", value) {
+			return item
+		}
+
+		var href string
+		if h := reItemHref.FindStringSubmatch(item); h != nil {
+			href = h[1][1 : len(h[1])-1]
+		}
+		report = append(report, fmt.Sprintf("Item '%s' is declared as scripted: property removed", href))
+
+		var props []string
+		for _, p := range strings.Fields(value) {
+			if p != "scripted" {
+				props = append(props, p)
+			}
+		}
+		if len(props) == 0 {
+			return item[:m[0]] + item[m[1]:]
+		}
+		return item[:m[2]] + quote + strings.Join(props, " ") + quote + item[m[3]:]
+	})
+
+	_, err = io.WriteString(w, sanitized)
+	return report, err
+}
+
+// isOPFResource reports whether an EPUB's resource is an OPF package
+// document.
+func isOPFResource(name string) bool {
+	return strings.ToLower(filepath.Ext(name)) == ".opf"
+}
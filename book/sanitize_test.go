@@ -0,0 +1,175 @@
+package book
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeContent(t *testing.T) {
+	unsafe := filepath.Join(t.TempDir(), "unsafe.epub")
+	if err := injectInEpub(unsafe, filepath.Join(testdataBooks, "pg6099.epub"), map[string][2]string{
+		"OEBPS/wrap0000.html": {"</body>", `<script>alert('XSS')</script><p onclick="alert('XSS')">Hello</p><div><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><image width="10" height="10" xlink:href="cover.png"/></svg></div></body>`},
+		"OEBPS/pgepub.css":    {"", "p { background: url(http://ha.ckers.org/xss.png) }\n"},
+		"OEBPS/content.opf":   {`<item href="wrap0000.html" id="coverpage-wrapper" media-type="application/xhtml+xml"/>`, `<item href="wrap0000.html" id="coverpage-wrapper" media-type="application/xhtml+xml" properties="svg scripted"/><item href="logo.svg" id="logo" media-type="image/svg+xml" properties='scripted'/>`},
+		"OEBPS/logo.svg":      {"", `<?xml version="1.0" encoding="utf-8"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10" onload="alert('XSS')"><script>alert('XSS')</script><rect width="10" height="10"/></svg>`},
+	}); err != nil {
+		t.Fatalf("Fail to prepare unsafe EPUB: %v", err)
+	}
+
+	b := New()
+	b.Path = unsafe
+	if err := b.CheckContentSecurity(); err != nil {
+		t.Fatalf("Fail to check content security of %s: %v", b.Path, err)
+	}
+	if !b.HasIssue() {
+		t.Fatalf("Unsafe content of %s is not detected", b.Path)
+	}
+
+	clean := filepath.Join(t.TempDir(), "clean.epub")
+	f, err := os.Create(clean)
+	if err != nil {
+		t.Fatalf("Fail to create %s: %v", clean, err)
+	}
+	defer f.Close()
+
+	report, err := b.SanitizeContent(f)
+	if err != nil {
+		t.Fatalf("Fail to sanitize content of %s: %v", b.Path, err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Fail to write %s: %v", clean, err)
+	}
+
+	if len(report) != 7 {
+		t.Errorf("Sanitizing report is not as expected.\nWant: 7 removals\nGot : %q", report)
+	}
+
+	cleanBook, err := NewFromFile(clean)
+	if err != nil {
+		t.Fatalf("Fail to read sanitized EPUB %s: %v", clean, err)
+	}
+	if cleanBook.Title == "" {
+		t.Errorf("Sanitized EPUB %s lost its metadata", clean)
+	}
+
+	if err := cleanBook.CheckContentSecurity(); err != nil {
+		t.Fatalf("Fail to check content security of %s: %v", clean, err)
+	}
+	if cleanBook.HasIssue() {
+		t.Errorf("Sanitized EPUB %s still has issues: %v", clean, cleanBook.Issues)
+	}
+
+	txt := new(bytes.Buffer)
+	if _, err := cleanBook.WriteText(txt, false); err != nil {
+		t.Fatalf("Fail to read text of %s: %v", clean, err)
+	}
+	if !strings.Contains(txt.String(), "Hello") {
+		t.Errorf("Sanitized EPUB %s lost safe content", clean)
+	}
+
+	zr, err := zip.OpenReader(clean)
+	if err != nil {
+		t.Fatalf("Fail to open %s: %v", clean, err)
+	}
+	defer zr.Close()
+
+	page, err := fs.ReadFile(zr, "OEBPS/wrap0000.html")
+	if err != nil {
+		t.Fatalf("Fail to read cover page of %s: %v", clean, err)
+	}
+	if !bytes.Contains(page, []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><image`)) {
+		t.Errorf("Sanitized EPUB %s lost its SVG content:\n%s", clean, page)
+	}
+
+	logo, err := fs.ReadFile(zr, "OEBPS/logo.svg")
+	if err != nil {
+		t.Fatalf("Fail to read SVG image of %s: %v", clean, err)
+	}
+	if want := `<?xml version="1.0" encoding="utf-8"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><rect width="10" height="10"/></svg>`; string(logo) != want {
+		t.Errorf("Sanitized EPUB %s SVG image is not as expected.\nWant: %s\nGot : %s", clean, want, logo)
+	}
+
+	opf, err := fs.ReadFile(zr, "OEBPS/content.opf")
+	if err != nil {
+		t.Fatalf("Fail to read package document of %s: %v", clean, err)
+	}
+	if !bytes.Contains(opf, []byte(`media-type="application/xhtml+xml" properties="svg"/><item href="logo.svg" id="logo" media-type="image/svg+xml"/>`)) {
+		t.Errorf("Sanitized EPUB %s is still declared as scripted:\n%s", clean, opf)
+	}
+}
+
+// injectInEpub copies an EPUB, replacing in the given resources the first
+// occurrence of a string by another one (or prefixing the resource if string
+// to replace is empty). Resources that do not exist are created.
+func injectInEpub(dst, src string, injections map[string][2]string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	zw := zip.NewWriter(w)
+	for _, f := range r.File {
+		inject, exists := injections[f.Name]
+		if !exists {
+			if err := zw.Copy(f); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		fw, err := zw.Create(f.Name)
+		if err != nil {
+			return err
+		}
+		if inject[0] == "" {
+			content = append([]byte(inject[1]), content...)
+		} else {
+			content = bytes.Replace(content, []byte(inject[0]), []byte(inject[1]), 1)
+		}
+		if _, err := fw.Write(content); err != nil {
+			return err
+		}
+	}
+
+	for name, inject := range injections {
+		if _, err := fs.Stat(r, name); err == nil {
+			continue
+		}
+
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write([]byte(inject[1])); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return w.Close()
+}
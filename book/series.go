@@ -0,0 +1,294 @@
+package book
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// seriesMaxGap is the number of consecutive missing indexes of a series
+	// whose length is unknown above which greater owned indexes are
+	// considered as outliers (like a wrongly guessed index) rather than as
+	// missing books.
+	seriesMaxGap = 20
+)
+
+var (
+	// knownSeries maps the normalized form of known series' names variants
+	// to their registry's entry.
+	knownSeries = make(map[string]*SeriesAuthority)
+
+	// reSeriesSuffix captures usual words that are appended to series' names
+	// (like "Foundation Series" or "Cycle de Fondation").
+	reSeriesSuffix = regexp.MustCompile(`(?i)^(?:(?:the|le|la|les|l')\s*)?(?:series|serie|série|cycle|saga|trilogy|trilogie)\s+(?:of\s+|de\s+|du\s+|des\s+|d')?|\s+(?:series|serie|série|cycle|saga|trilogy|trilogie)$`)
+)
+
+// SeriesAuthority is a user-maintained entry of the series' registry. It
+// defines the canonical name of a series and the variants it is known under.
+type SeriesAuthority struct {
+	// Name is the canonical name of the series.
+	Name string `json:"name"`
+
+	// Aliases lists other names of the series (like translated names or
+	// "Foundation Series" for "Foundation").
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Count is the total number of books of the series, if known.
+	Count int `json:"count,omitempty"`
+}
+
+// LoadSeries reads a list of SeriesAuthority from a JSON file and adds them to
+// the series' registry.
+func LoadSeries(path string) error {
+	//#nosec G304 -- path is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var series []SeriesAuthority
+	if err := json.NewDecoder(f).Decode(&series); err != nil {
+		return fmt.Errorf("fail to decode series registry %s: %v", path, err)
+	}
+
+	return AddSeries(series...)
+}
+
+// AddSeries adds SeriesAuthority entries to the series' registry.
+// AddSeries fails without adding any entry if a name is declared as the
+// variant of two different series.
+func AddSeries(series ...SeriesAuthority) error {
+	entries := make(map[string]*SeriesAuthority)
+
+	for i := range series {
+		s := series[i]
+		if s.Name = strings.TrimSpace(s.Name); s.Name == "" {
+			return fmt.Errorf("invalid series #%d: no name", i+1)
+		}
+
+		for _, alias := range append([]string{s.Name}, s.Aliases...) {
+			key := seriesKey(alias)
+			if key == "" {
+				continue
+			}
+
+			for _, known := range []map[string]*SeriesAuthority{knownSeries, entries} {
+				if e, exists := known[key]; exists && e.Name != s.Name {
+					return fmt.Errorf("invalid series #%d: '%s' is already declared as a variant of '%s'", i+1, alias, e.Name)
+				}
+			}
+
+			entries[key] = &s
+		}
+	}
+
+	for key, s := range entries {
+		knownSeries[key] = s
+	}
+
+	return nil
+}
+
+// CanonicalSeries returns the canonical name of a series as defined in the
+// series' registry. Series that are not known are returned as-is.
+func CanonicalSeries(name string) string {
+	if s, known := knownSeries[seriesKey(name)]; known {
+		return s.Name
+	}
+	return name
+}
+
+// SeriesCount returns the total number of books of a series as defined in the
+// series' registry or 0 if it is not known.
+func SeriesCount(name string) int {
+	if s, known := knownSeries[seriesKey(name)]; known {
+		return s.Count
+	}
+	return 0
+}
+
+// setCanonicalSeries replaces Book's Series by its canonical name and verifies
+// that SeriesIndex is consistent with the series' known length.
+func (b *Book) setCanonicalSeries() {
+	if b.Series == "" {
+		return
+	}
+
+	if canonical := CanonicalSeries(b.Series); canonical != b.Series {
+		Verbose.Printf("use canonical name '%s' for series '%s'", canonical, b.Series)
+		b.Series = canonical
+	}
+
+	if count := SeriesCount(b.Series); count > 0 && b.SeriesIndex > float64(count) {
+		b.ReportWarning("SeriesIndex %v exceeds the number of books of series '%s' (%d)", b.SeriesIndex, b.Series, count)
+	}
+}
+
+// SeriesReport summarizes the books of a series found in a collection.
+type SeriesReport struct {
+	// Name is the name of the series.
+	Name string
+
+	// Spellings lists the other names found for the series.
+	Spellings []string `json:",omitempty"`
+
+	// Count is the total number of books of the series, if known.
+	Count int `json:",omitempty"`
+
+	// Owned lists the indexes of the series' books found in the collection.
+	Owned []float64 `json:",omitempty"`
+
+	// Missing lists the indexes of the series' books that are not found in
+	// the collection.
+	Missing []float64 `json:",omitempty"`
+
+	// Duplicates lists the indexes found for more than one book.
+	Duplicates []float64 `json:",omitempty"`
+
+	// Outliers lists the owned indexes that are not used to find missing
+	// ones as they exceed the series' known length or are far beyond the
+	// other owned indexes, likely wrongly guessed.
+	Outliers []float64 `json:",omitempty"`
+}
+
+// NewSeriesReports analyses the series of a collection of Books. Books'
+// Series are compared using their canonical name and similar names that
+// share at least one author (compared using their canonical name) are
+// considered as inconsistent spellings of the same series, the most frequent
+// one being retained as the series' name.
+// Missing indexes are found up to the series' known length or, if unknown, up
+// to the greatest owned index that is not an outlier, that is an index
+// following more than seriesMaxGap missing ones.
+func NewSeriesReports(books []*Book) []*SeriesReport {
+	count := make(map[string]int)
+	authors := make(map[string]map[string]bool)
+	for _, b := range books {
+		if b.Series == "" {
+			continue
+		}
+
+		name := CanonicalSeries(b.Series)
+		count[name]++
+
+		if authors[name] == nil {
+			authors[name] = make(map[string]bool)
+		}
+		for _, author := range b.Authors {
+			authors[name][nameKey(CanonicalName(author))] = true
+		}
+	}
+
+	names := make([]string, 0, len(count))
+	for name := range count {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if count[names[i]] != count[names[j]] {
+			return count[names[i]] > count[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	reports := make(map[string]*SeriesReport)
+	for _, name := range names {
+		reports[name] = &SeriesReport{Name: name, Count: SeriesCount(name)}
+	}
+	for _, group := range groupVariants(names, func(name1, name2 string) bool {
+		return shareAuthor(authors[name1], authors[name2]) && areSeriesVariants(name1, name2)
+	}) {
+		r := reports[group[0]]
+		r.Spellings = group[1:]
+		for _, variant := range group[1:] {
+			reports[variant] = r
+		}
+	}
+
+	seen := make(map[*SeriesReport]map[float64]int)
+	for _, b := range books {
+		if b.Series == "" || b.SeriesIndex == 0 {
+			continue
+		}
+
+		r := reports[CanonicalSeries(b.Series)]
+		if seen[r] == nil {
+			seen[r] = make(map[float64]int)
+		}
+
+		if seen[r][b.SeriesIndex]++; seen[r][b.SeriesIndex] == 1 {
+			r.Owned = append(r.Owned, b.SeriesIndex)
+		} else if seen[r][b.SeriesIndex] == 2 {
+			r.Duplicates = append(r.Duplicates, b.SeriesIndex)
+		}
+	}
+
+	var list []*SeriesReport
+	for _, name := range names {
+		r := reports[name]
+		if r.Name != name {
+			continue
+		}
+
+		sort.Float64s(r.Owned)
+		sort.Float64s(r.Duplicates)
+
+		last := float64(r.Count)
+		for _, idx := range r.Owned {
+			switch {
+			case r.Count > 0:
+				if idx > last {
+					r.Outliers = append(r.Outliers, idx)
+				}
+
+			// Owned indexes being sorted, indexes following an outlier are
+			// outliers too.
+			case len(r.Outliers) > 0 || math.Floor(idx)-last-1 > seriesMaxGap:
+				r.Outliers = append(r.Outliers, idx)
+
+			default:
+				last = math.Floor(idx)
+			}
+		}
+		for i := 1.0; i <= last; i++ {
+			if _, owned := seen[r][i]; !owned {
+				r.Missing = append(r.Missing, i)
+			}
+		}
+
+		list = append(list, r)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return NormalizeString(list[i].Name) < NormalizeString(list[j].Name)
+	})
+
+	return list
+}
+
+// areSeriesVariants checks whether two series' names are likely to be
+// spelling variants of the same series.
+func areSeriesVariants(name1, name2 string) bool {
+	return compareStrings(seriesKey(name1), seriesKey(name2)) >= AreAlmostTheSame
+}
+
+// shareAuthor checks whether two sets of authors' names have at least one
+// author in common.
+func shareAuthor(authors1, authors2 map[string]bool) bool {
+	for author := range authors1 {
+		if authors2[author] {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesKey returns the normalized form of a series' name used to look-up the
+// series' registry.
+func seriesKey(name string) string {
+	return strings.Join(strings.Fields(NormalizeString(reSeriesSuffix.ReplaceAllString(strings.TrimSpace(name), ""))), " ")
+}
@@ -0,0 +1,107 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/pirmd/verify"
+)
+
+func resetSeries() {
+	knownSeries = make(map[string]*SeriesAuthority)
+}
+
+func TestAddSeries(t *testing.T) {
+	testCases := []struct {
+		in  []SeriesAuthority
+		err bool
+	}{
+		{[]SeriesAuthority{{Name: "Foundation", Aliases: []string{"Fondation"}, Count: 7}}, false},
+		{[]SeriesAuthority{{Aliases: []string{"Fondation"}}}, true},
+		{[]SeriesAuthority{{Name: "Foundation", Aliases: []string{"Fondation"}}, {Name: "Fondation", Aliases: []string{"Foundation"}}}, true},
+	}
+
+	defer resetSeries()
+	for _, tc := range testCases {
+		resetSeries()
+		if err := AddSeries(tc.in...); (err != nil) != tc.err {
+			t.Errorf("Adding series %#v failed:\nWant error: %v\nGot : %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestCanonicalSeries(t *testing.T) {
+	Verbose, Debug = verify.NewLogger(t), verify.NewLogger(t)
+
+	defer resetSeries()
+	if err := LoadSeries("testdata/series/series.json"); err != nil {
+		t.Fatalf("fail to load series registry: %v", err)
+	}
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"Foundation", "Foundation"},
+		{"Fondation", "Foundation"},
+		{"Foundation Series", "Foundation"},
+		{"Le cycle de Fondation", "Foundation"},
+		{"compagnie des glaces", "La compagnie des glaces"},
+		{"Dune", "Dune"},
+	}
+
+	for _, tc := range testCases {
+		if got := CanonicalSeries(tc.in); got != tc.want {
+			t.Errorf("Canonical name of series %#v failed.\nWant: %#v\nGot : %#v", tc.in, tc.want, got)
+		}
+	}
+
+	t.Run("GuessFromMetadata", func(t *testing.T) {
+		b := &Book{Title: "Fondation #9", Report: NewReport()}
+		if err := b.GuessFromMetadata(); err != nil {
+			t.Fatalf("fail to guess from metadata: %v", err)
+		}
+
+		if b.Series != "Foundation" || b.SeriesIndex != 9 || len(b.Report.Warnings) != 1 {
+			t.Errorf("Guessing canonical series failed.\nWant: Foundation #9 with one warning\nGot : %s #%v (%v)", b.Series, b.SeriesIndex, b.Report.Warnings)
+		}
+	})
+}
+
+func TestNewSeriesReports(t *testing.T) {
+	defer resetSeries()
+	if err := AddSeries(SeriesAuthority{Name: "Foundation", Count: 7}); err != nil {
+		t.Fatalf("fail to add series: %v", err)
+	}
+
+	in := []*Book{
+		{Series: "Foundation", SeriesIndex: 1, Authors: []string{"Isaac Asimov"}},
+		{Series: "Fondation", SeriesIndex: 2, Authors: []string{"Asimov, Isaac"}},
+		{Series: "Foundation Series", SeriesIndex: 2, Authors: []string{"Isaac Asimov"}},
+		{Series: "Foundation", SeriesIndex: 5, Authors: []string{"Isaac Asimov"}},
+		{Series: "Foundation", SeriesIndex: 9999, Authors: []string{"Isaac Asimov"}},
+		{Series: "Dune", SeriesIndex: 1, Authors: []string{"Frank Herbert"}},
+		{Series: "Dune", SeriesIndex: 4, Authors: []string{"Frank Herbert"}},
+		{Series: "Dune", SeriesIndex: 4, Authors: []string{"Frank Herbert"}},
+		{Series: "Dune", SeriesIndex: 9999, Authors: []string{"Frank Herbert"}},
+		{Series: "Dune", SeriesIndex: 10000, Authors: []string{"Frank Herbert"}},
+		{Series: "Harry Potter", SeriesIndex: 1, Authors: []string{"J. K. Rowling"}},
+		{Series: "Harry Hole", SeriesIndex: 1, Authors: []string{"Jo Nesbø"}},
+		{Series: "Star Wars", SeriesIndex: 1, Authors: []string{"Timothy Zahn"}},
+		{Series: "Star Trek", SeriesIndex: 1, Authors: []string{"Diane Duane"}},
+		{Title: "Sun Company"},
+	}
+
+	want := []*SeriesReport{
+		{Name: "Dune", Owned: []float64{1, 4, 9999, 10000}, Missing: []float64{2, 3}, Duplicates: []float64{4}, Outliers: []float64{9999, 10000}},
+		{Name: "Foundation", Spellings: []string{"Fondation"}, Count: 7, Owned: []float64{1, 2, 5, 9999}, Missing: []float64{3, 4, 6, 7}, Duplicates: []float64{2}, Outliers: []float64{9999}},
+		{Name: "Harry Hole", Owned: []float64{1}},
+		{Name: "Harry Potter", Owned: []float64{1}},
+		{Name: "Star Trek", Owned: []float64{1}},
+		{Name: "Star Wars", Owned: []float64{1}},
+	}
+
+	got := NewSeriesReports(in)
+	if failure := verify.Equal(want, got); failure != nil {
+		t.Errorf("Reporting series failed:\n%v", failure)
+	}
+}
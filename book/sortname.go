@@ -0,0 +1,122 @@
+package book
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// nameParticles lists usual particles found in surnames (like 'de' or
+	// 'van der').
+	nameParticles = []string{
+		"d'", "da", "das", "de", "degli", "del", "della", "den", "der", "des",
+		"di", "do", "dos", "du", "la", "le", "les", "ten", "ter", "van", "von",
+		"zu",
+	}
+
+	// nameSuffixes lists usual generational suffixes of names.
+	nameSuffixes = []string{"jr", "jr.", "sr", "sr.", "ii", "iii", "iv"}
+)
+
+// SortName returns the sortable form of a person's name, that is
+// "Surname, Forename" (like "Verne, Jules" for "Jules Verne").
+// Particles are kept with the surname when capitalized (like "Le Guin,
+// Ursula K.") and moved after the forename otherwise (like "Beethoven, Ludwig
+// van" or "La Fontaine, Jean de").
+// Names that contain a comma are considered to be already in their sortable
+// form and are returned as-is.
+func SortName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, ",") {
+		return name
+	}
+
+	words := strings.Fields(name)
+
+	var suffix string
+	if len(words) > 2 && isInList(strings.ToLower(words[len(words)-1]), nameSuffixes) {
+		suffix, words = words[len(words)-1], words[:len(words)-1]
+	}
+
+	if len(words) < 2 {
+		return name
+	}
+
+	i := len(words) - 1
+	for i > 1 && isParticle(words[i-1]) {
+		i--
+	}
+	forename, surname := words[:i], words[i:]
+
+	var j int
+	for j < len(surname)-1 && isParticle(surname[j]) && !startsWithUpper(surname[j]) {
+		j++
+	}
+
+	sortname := strings.Join(surname[j:], " ") + ", " + strings.Join(append(forename[:len(forename):len(forename)], surname[:j]...), " ")
+	if suffix != "" {
+		sortname += ", " + suffix
+	}
+
+	return sortname
+}
+
+// SortName returns the sortable form of the name of one of Book's
+// contributors. It uses Book's FileAs information if known, otherwise it is
+// computed using SortName function.
+func (b Book) SortName(name string) string {
+	if fileAs, exists := b.FileAs[name]; exists && fileAs != "" {
+		return fileAs
+	}
+	return SortName(name)
+}
+
+// SortByAuthor sorts a list of Books by the sortable name of their first
+// Author, then by Series, SeriesIndex and Title (see Book.SortKey).
+func SortByAuthor(books []*Book) {
+	sort.SliceStable(books, func(i, j int) bool {
+		return books[i].SortKey() < books[j].SortKey()
+	})
+}
+
+// SortKey returns a key that orders Books by the sortable name of their first
+// Author, then by Series, SeriesIndex and Title when compared as strings.
+func (b Book) SortKey() string {
+	return strings.Join([]string{
+		b.sortAuthor(),
+		NormalizeString(b.Series),
+		fmt.Sprintf("%020.6f", b.SeriesIndex),
+		NormalizeString(b.Title),
+	}, "\x00")
+}
+
+// sortAuthor returns a normalized version of the sortable name of Book's
+// first Author.
+func (b Book) sortAuthor() string {
+	if len(b.Authors) == 0 {
+		return ""
+	}
+	return NormalizeString(b.SortName(b.Authors[0]))
+}
+
+// isParticle checks whether a word is a surname's particle.
+func isParticle(word string) bool {
+	return isInList(strings.ToLower(word), nameParticles)
+}
+
+func startsWithUpper(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return unicode.IsUpper(r)
+}
+
+func isInList(s string, list []string) bool {
+	for _, l := range list {
+		if s == l {
+			return true
+		}
+	}
+	return false
+}
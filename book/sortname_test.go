@@ -0,0 +1,58 @@
+package book
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSortName(t *testing.T) {
+	testCases := []struct {
+		in  string
+		out string
+	}{
+		{"Jules Verne", "Verne, Jules"},
+		{"Ursula K. Le Guin", "Le Guin, Ursula K."},
+		{"Ludwig van Beethoven", "Beethoven, Ludwig van"},
+		{"Vincent van der Heijden", "Heijden, Vincent van der"},
+		{"Jean de La Fontaine", "La Fontaine, Jean de"},
+		{"Charles de Gaulle", "Gaulle, Charles de"},
+		{"Martin Luther King Jr.", "King, Martin Luther, Jr."},
+		{"Herodotus", "Herodotus"},
+		{"Verne, Jules", "Verne, Jules"},
+	}
+
+	for _, tc := range testCases {
+		if got := SortName(tc.in); got != tc.out {
+			t.Errorf("Sortable name of %#v failed.\nWant: %v\nGot : %v", tc.in, tc.out, got)
+		}
+	}
+}
+
+func TestSortByAuthor(t *testing.T) {
+	books := []*Book{
+		{Title: "Vingt mille lieues sous les mers", Authors: []string{"Jules Verne"}},
+		{Title: "The Left Hand of Darkness", Authors: []string{"Ursula K. Le Guin"}},
+		{Title: "A Wizard of Earthsea", Authors: []string{"Ursula K. Le Guin"}, Series: "Earthsea", SeriesIndex: 1},
+		{Title: "Fables", Authors: []string{"Jean de La Fontaine"}},
+		{Title: "Alice's Adventures in Wonderland", Authors: []string{"Lewis Carroll"}, FileAs: map[string]string{"Lewis Carroll": "Dodgson, Charles Lutwidge"}},
+	}
+
+	SortByAuthor(books)
+
+	var got []string
+	for _, b := range books {
+		got = append(got, b.Title)
+	}
+
+	want := []string{
+		"Alice's Adventures in Wonderland",
+		"Fables",
+		"The Left Hand of Darkness",
+		"A Wizard of Earthsea",
+		"Vingt mille lieues sous les mers",
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Sorting books by author failed.\nWant: %v\nGot : %v", want, got)
+	}
+}
@@ -0,0 +1,179 @@
+package book
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/pirmd/epub"
+
+	"github.com/pirmd/libro/book/htmlutil"
+)
+
+var (
+	// WordsPerPage is the average number of words of a printed page used to
+	// estimate Book's PageCount.
+	WordsPerPage = 250
+
+	// WordsPerMinute is the average reading speed used to estimate Book's
+	// reading time.
+	WordsPerMinute = 230
+)
+
+// TextStats represents statistics about the text of a Book.
+type TextStats struct {
+	// Words is the number of words of the text.
+	Words int
+
+	// Characters is the number of characters of the text, spaces excluded.
+	Characters int
+
+	// ReadingTime is the estimated time needed to read the text (see
+	// WordsPerMinute).
+	ReadingTime time.Duration
+
+	// Pages is the estimated number of printed pages of the text (see
+	// WordsPerPage).
+	Pages int64
+}
+
+// add updates TextStats with the statistics of a chunk of text.
+func (s *TextStats) add(txt string) {
+	s.Words += len(strings.Fields(txt))
+	for _, r := range txt {
+		if !unicode.IsSpace(r) {
+			s.Characters++
+		}
+	}
+
+	if WordsPerMinute > 0 {
+		s.ReadingTime = (time.Duration(s.Words) * time.Minute / time.Duration(WordsPerMinute)).Round(time.Minute)
+	}
+
+	if WordsPerPage > 0 {
+		s.Pages = int64(math.Ceil(float64(s.Words) / float64(WordsPerPage)))
+	}
+}
+
+// WriteText writes the text of Book's content, following its reading order,
+// to w. Content is converted to plain text or, if markdown is set, to
+// Markdown. Boundaries between chapters (reading order's items) are marked by
+// a separation line.
+// WriteText returns the statistics of the text.
+func (b *Book) WriteText(w io.Writer, markdown bool) (*TextStats, error) {
+	stats := &TextStats{}
+
+	sep := "\n\n* * *\n\n"
+	if markdown {
+		sep = "\n\n---\n\n"
+	}
+
+	var chapters int
+	if err := epub.WalkReadingContent(b.Path, func(r io.Reader, fi fs.FileInfo) error {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		raw, err := htmlutil.GetRawTextFromHTML(bytes.NewReader(content))
+		if err != nil {
+			return err
+		}
+
+		rawTxt, err := io.ReadAll(raw)
+		if err != nil {
+			return err
+		}
+
+		txt := rawTxt
+		if markdown {
+			md, err := htmlutil.GetMarkdownFromHTML(bytes.NewReader(content))
+			if err != nil {
+				return err
+			}
+
+			if txt, err = io.ReadAll(md); err != nil {
+				return err
+			}
+		}
+
+		if len(bytes.TrimSpace(txt)) == 0 {
+			Debug.Printf("skip '%s' that has no text", fi.Name())
+			return nil
+		}
+		stats.add(string(rawTxt))
+
+		if chapters > 0 {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
+		}
+		chapters++
+
+		_, err = w.Write(txt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if chapters > 0 {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// WalkText walks Book's content following its reading order and calls fn
+// with the name and the plain text of each chapter (reading order's items).
+// Chapters without text are skipped. Returning an error from fn stops the
+// walk and the error is returned by WalkText.
+func (b *Book) WalkText(fn func(chapter string, txt string) error) error {
+	return epub.WalkReadingContent(b.Path, func(r io.Reader, fi fs.FileInfo) error {
+		raw, err := htmlutil.GetRawTextFromHTML(r)
+		if err != nil {
+			return err
+		}
+
+		txt, err := io.ReadAll(raw)
+		if err != nil {
+			return err
+		}
+
+		if len(bytes.TrimSpace(txt)) == 0 {
+			Debug.Printf("skip '%s' that has no text", fi.Name())
+			return nil
+		}
+
+		return fn(fi.Name(), string(txt))
+	})
+}
+
+// TextStats computes the statistics of the text of Book's content.
+func (b *Book) TextStats() (*TextStats, error) {
+	return b.WriteText(io.Discard, false)
+}
+
+// EstimatePageCount sets Book's PageCount from the number of words of its
+// content (see WordsPerPage) if PageCount is unknown.
+func (b *Book) EstimatePageCount() error {
+	if b.PageCount != 0 {
+		return nil
+	}
+
+	stats, err := b.TextStats()
+	if err != nil {
+		return err
+	}
+
+	if stats.Pages > 0 {
+		Verbose.Printf("set empty PageCount to estimated %d pages (%d words)", stats.Pages, stats.Words)
+		b.PageCount = stats.Pages
+	}
+	return nil
+}
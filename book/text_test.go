@@ -0,0 +1,56 @@
+package book
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteText(t *testing.T) {
+	b := New()
+	b.Path = filepath.Join(testdataBooks, "pg11.epub")
+
+	for _, markdown := range []bool{false, true} {
+		txt := new(bytes.Buffer)
+		stats, err := b.WriteText(txt, markdown)
+		if err != nil {
+			t.Fatalf("Fail to write text of %s: %v", b.Path, err)
+		}
+
+		if !strings.Contains(txt.String(), "Down the Rabbit-Hole") {
+			t.Errorf("Text of %s is missing its content (markdown: %v)", b.Path, markdown)
+		}
+
+		if stats.Words == 0 || stats.Characters <= stats.Words || stats.ReadingTime == 0 || stats.Pages == 0 {
+			t.Errorf("Statistics of %s are not complete (markdown: %v): %+v", b.Path, markdown, stats)
+		}
+	}
+}
+
+func TestEstimatePageCount(t *testing.T) {
+	b := New()
+	b.Path = filepath.Join(testdataBooks, "pg11.epub")
+
+	if err := b.EstimatePageCount(); err != nil {
+		t.Fatalf("Fail to estimate page count of %s: %v", b.Path, err)
+	}
+
+	stats, err := b.TextStats()
+	if err != nil {
+		t.Fatalf("Fail to compute text statistics of %s: %v", b.Path, err)
+	}
+
+	if want := int64((stats.Words + WordsPerPage - 1) / WordsPerPage); b.PageCount != want {
+		t.Errorf("Page count estimation failed.\nWant: %v\nGot : %v", want, b.PageCount)
+	}
+
+	b.PageCount = 42
+	if err := b.EstimatePageCount(); err != nil {
+		t.Fatalf("Fail to estimate page count of %s: %v", b.Path, err)
+	}
+
+	if b.PageCount != 42 {
+		t.Errorf("Known page count should not be estimated.\nWant: 42\nGot : %v", b.PageCount)
+	}
+}
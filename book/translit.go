@@ -0,0 +1,110 @@
+package book
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// translitTable maps lower-case Cyrillic and Greek letters to their Latin
+	// transliteration. Cyrillic follows a simplified BGN/PCGN romanization
+	// (covering Russian, Ukrainian, Belarusian, Serbian and Macedonian
+	// letters) and Greek follows a simplified ELOT 743 romanization.
+	translitTable = map[rune]string{
+		// Cyrillic
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+		'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+		'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+		'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+		'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+		'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+		'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+		'ѓ': "gj", 'ќ': "kj", 'ѕ': "dz",
+
+		// Greek
+		'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+		'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+		'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+		'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	}
+
+	// translitDigraphs maps lower-case Greek diphthongs that are not
+	// transliterated letter by letter.
+	translitDigraphs = map[string]string{
+		"ου": "ou", "αυ": "av", "ευ": "ev", "ηυ": "iv",
+	}
+)
+
+// Transliterate converts Cyrillic and Greek letters of a string to their
+// Latin transliteration (like "Лев Толстой" to "Lev Tolstoy"). Other
+// characters are kept as-is.
+func Transliterate(s string) string {
+	var t strings.Builder
+
+	var skip bool
+	for i, r := range s {
+		if skip {
+			skip = false
+			continue
+		}
+
+		// Width is taken from the string rather than from the rune as invalid
+		// UTF-8 bytes are decoded as utf8.RuneError that is 3 bytes long.
+		_, w := utf8.DecodeRuneInString(s[i:])
+		latin, known := translitRune(r)
+
+		next, sz := utf8.DecodeRuneInString(s[i+w:])
+		if digraph, isDigraph := translitDigraphs[string(greekBase(unicode.ToLower(r)))+string(greekBase(unicode.ToLower(next)))]; isDigraph {
+			latin, known, skip = digraph, true, true
+			next, _ = utf8.DecodeRuneInString(s[i+w+sz:])
+		}
+
+		if !known {
+			t.WriteString(s[i : i+w])
+			continue
+		}
+
+		if unicode.IsUpper(r) && latin != "" {
+			// Upper-case letters are transliterated in upper-case if followed
+			// by another upper-case letter (like in "ЩИ" -> "SHCHI"), in
+			// title-case otherwise (like in "Щи" -> "Shchi").
+			if unicode.IsUpper(next) {
+				latin = strings.ToUpper(latin)
+			} else {
+				first, sz := utf8.DecodeRuneInString(latin)
+				latin = string(unicode.ToUpper(first)) + latin[sz:]
+			}
+		}
+		t.WriteString(latin)
+	}
+
+	return t.String()
+}
+
+// translitRune returns the Latin transliteration of a Cyrillic or Greek
+// letter. Accented Greek letters are transliterated without their accents.
+func translitRune(r rune) (string, bool) {
+	lr := unicode.ToLower(r)
+	if latin, known := translitTable[lr]; known {
+		return latin, true
+	}
+
+	if base := greekBase(lr); base != lr {
+		latin, known := translitTable[base]
+		return latin, known
+	}
+
+	return "", false
+}
+
+// greekBase returns the base letter of an accented Greek letter.
+func greekBase(r rune) rune {
+	if !unicode.Is(unicode.Greek, r) {
+		return r
+	}
+	base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+	return base
+}
@@ -0,0 +1,55 @@
+package book
+
+import (
+	"testing"
+)
+
+func TestTransliterate(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"Лев Толстой", "Lev Tolstoy"},
+		{"Война и мир", "Voyna i mir"},
+		{"ЩИ", "SHCHI"},
+		{"Щи", "Shchi"},
+		{"Тарас Шевченко: Кобзар", "Taras Shevchenko: Kobzar"},
+		{"Ὀδύσσεια", "Odysseia"},
+		{"Ομήρου Ιλιάς", "Omirou Ilias"},
+		{"Jules Verne", "Jules Verne"},
+		{"Éditions Gallimard", "Éditions Gallimard"},
+		{"老子", "老子"},
+		{"\xffЛев\xfe", "\xffLev\xfe"},
+		{"Лев\xff", "Lev\xff"},
+	}
+
+	for _, tc := range testCases {
+		if got := Transliterate(tc.in); got != tc.want {
+			t.Errorf("Transliterating %#v failed.\nWant: %#v\nGot : %#v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestCompareTransliterated(t *testing.T) {
+	testCases := []struct {
+		in1, in2 string
+		want     SimilarityLevel
+	}{
+		{"Лев Толстой", "Lev Tolstoy", AreTheSame},
+		{"Лев Толстой", "Lev Tolstoï", AreTheSame},
+		{"Ὀδύσσεια", "Odysseia", AreTheSame},
+		{"Лев Толстой", "Jules Verne", AreNotTheSame},
+	}
+
+	for _, tc := range testCases {
+		if got := compareNormalizedStrings(tc.in1, tc.in2); got != tc.want {
+			t.Errorf("Fail to compare %#v vs. %#v.\nWant: %v\nGot : %v", tc.in1, tc.in2, tc.want, got)
+		}
+	}
+
+	c := NewComparator()
+	c.Transliterate = false
+	if got := c.Compare(&Book{Title: "Лев Толстой"}, &Book{Title: "Lev Tolstoy"}).Field(FieldTitle).Level; got != AreNotTheSame {
+		t.Errorf("Fail to compare without transliteration.\nWant: %v\nGot : %v", AreNotTheSame, got)
+	}
+}
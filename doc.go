@@ -46,6 +46,28 @@
 //   - lower:  convert string to lower-case
 //   - title:  convert string to title-case
 //   - upper:  convert string to upper-case
+//   - wrap :  wrap text to the given width, keeping Markdown's lists and
+//     quotes formatting (like `{{wrap 80 .Description}}`)
+//   - language management:
+//   - langname: get the name of a language in the given locale (like
+//     `{{langname "en" .Language}}`), 'self' locale gets the name in the
+//     language itself.
+//   - baselang: get the language code without script or region
+//   - names management:
+//   - sortname: get the sortable form of a person's name (like
+//     `{{index .Authors 0 | sortname}}` giving "Verne, Jules"). sortname
+//     only computes it from the name, use `{{.SortName (index .Authors 0)}}`
+//     to prefer Book's known sortable names (from EPUB's 'file-as' metadata).
+//   - translit: transliterate Cyrillic and Greek letters to Latin ones (like
+//     `{{index .Authors 0 | translit | sanitizeFilename}}` giving "Lev
+//     Tolstoy" for "Лев Толстой").
+//   - ISBN management:
+//   - hyphenisbn    : get the hyphenated form of an ISBN (like
+//     "978-2-07-036822-8")
+//   - isbngroup     : get the language area or country of an ISBN (like
+//     "French language")
+//   - isbnregistrant: get the registrant (publisher) prefix of an ISBN (like
+//     "978-2-07")
 //   - serialization:
 //   - toJSON      : converts an interface to JSON representation.
 //   - toPrettyJSON: converts an interface to an easy-to-read JSON representation.
@@ -84,6 +106,10 @@
 //
 //	libro info "my_book.epub" | libro insert -rename='{{ tmpl "shortname.gotmpl" . | nospace }}
 //
+// or
+//
+//	libro info -markdown-description -format='{{wrap 80 .Description}}' my_book.epub
+//
 // User-defined templates can be loaded using specific flags like:
 //
 //	libro info -format-tmpl=$HOME/books/my_template.gotmpl -format='{{template "my_template.gotmpl" .}}' "my_book.epub"
@@ -102,7 +128,24 @@
 //     is only used to complete information obtained from EPUB's metadata or
 //     guessed;
 //   - guessed information are usually not preferred over EPUB's metadata or
-//     online information
+//     online information;
+//   - only contributors with an author role (MARC relator 'aut', default for
+//     EPUB's creators without role) are considered as Book's Authors.
+//     Translators ('trl') and other contributors (like illustrators 'ill' or
+//     editors 'edt') are kept apart and can be edited in Book's JSON
+//     ("Translators" and "Contributors" attributes).
+//   - ISBN are classified by the format of the edition they are attributed to
+//     (ebook, print or audio) using annotations found in EPUB's content
+//     (like "ISBN 978-... (epub)" or "ISBN papier : 978-..."). The ebook
+//     edition's ISBN is preferred as Book's ISBN, others are kept as
+//     "AlternateISBN";
+//   - identifiers other than ISBN (ISSN, ASIN, DOI, OCLC, LCCN, Googlebooks,
+//     OpenLibrary, UUID or Calibre ones) are collected from EPUB's metadata or
+//     online information into the "Identifiers" attribute, their check-digits
+//     are verified when defined (ISSN, EAN-13). They can be set using `libro
+//     edit -set Identifier.asin=B00XXXXXXX`. Books sharing one of them (except
+//     ISSN that identifies a serial, UUID that identifies a file and Calibre
+//     ones that are local to a Calibre library) are considered the same.
 //
 // When editing book's information (using `libro edit`), user is only asked to review information if:
 //   - key attributes are not filled,
@@ -114,10 +157,143 @@
 //
 // `libro` can run guessers to complete (and/or confirm) Book's metadata. Current guessers are:
 //   - guess Title, Series, Authors or Language from Book's filename,
+//   - guess Authors, Series or Title from Book's parent directories according
+//     to directory layouts given using `-dir-layout` flag (like
+//     '{Authors}/{Series}/{SeriesIndex} - {Title}'). There is no default
+//     layout as a layout matches any folder whose last elements look like it,
 //   - guess Series information from Book's Title or SubTitle,
-//   - guess ISBN by extracting it from the EPUB's content.
+//   - guess ISBN by extracting it from the EPUB's content,
+//   - guess Publisher, PublishedDate, OriginalTitle, OriginalPublishedDate,
+//     OriginalLanguage, Translators or Edition by parsing the EPUB's title
+//     and copyright pages,
+//   - guess Language by analyzing the EPUB's content. A warning is raised if
+//     detected Language differs from the declared one.
 //     Use of guessers is governed by the `-use-guesser` flag of `libro info` sub-command.
 //
+// Additional guessers and cleaners can be loaded from a JSON file using the
+// `-guesser-rules` flag of `libro info`. Each rule defines a target ('path',
+// 'title', 'subtitle' or 'content') and a regexp whose named capturing groups
+// correspond to Book's attributes. Cleaners (`"cleaner": true`, only for
+// 'title' and 'subtitle') replace existing attributes instead of only
+// completing missing ones. User-defined rules are run in order and take
+// precedence over built-in ones:
+//
+//	[
+//	  {"target": "path", "regexp": "^(?:.*/)?(?P<Title>.+) - (?P<Authors>.+)\\.epub$"},
+//	  {"target": "title", "cleaner": true, "regexp": "^(?P<Title>.+) \\(roman\\)$"}
+//	]
+//
+// # AUTHORS
+//
+// `libro` can be given an authority file that lists the canonical spelling of
+// authors' names, their aliases and their pseudonyms using `-authority` flag
+// of `libro info`. Aliases are replaced by the canonical name, pseudonyms are
+// kept as-is but are considered as the same person when comparing books:
+//
+//	[
+//	  {"name": "H. G. Wells", "aliases": ["H.G. Wells", "Wells, Herbert George"]},
+//	  {"name": "Romain Gary", "pseudonyms": ["Émile Ajar"]}
+//	]
+//
+// `libro authors` lists the authors found in the library together with
+// the names that look like variants of the same author. `libro authors
+// -suggest` prints these suggested merges in the authority file's format.
+//
+// # SERIES
+//
+// `libro` can be given a series' registry that lists the canonical name of
+// series, their aliases and their number of books when known, using
+// `-series-registry` flag of `libro info -use-guesser`. Series' aliases are
+// replaced by the canonical name once Book's information have been guessed:
+//
+//	[
+//	  {"name": "Foundation", "aliases": ["Fondation"], "count": 7}
+//	]
+//
+// `libro series` reports, for each series found in the library, the owned
+// indexes, the missing ones, the indexes owned several times and the
+// inconsistent spellings of the series' name (similar names of series that
+// share an author). Missing indexes are only looked for up to the series'
+// length when known, owned indexes beyond it being reported as outliers, or
+// else up to the greatest owned index that does not follow more than 20
+// missing ones.
+//
+// # COMPARISON
+//
+// `libro` compares books' information to decide whether guessed or online
+// information is about the same book. Books are considered the same when
+// their ISBN are the same and their Title, Authors, Publisher and
+// PublishedDate are similar enough.
+//
+// `libro compare A.json B.json` details why two books are or are not
+// considered the same: normalized values, similarity (between 0 and 1) and
+// similarity level of each field as well as an overall score weighted by
+// each field's importance. When fields disagree (books are almost or maybe the
+// same), a score above 'almost_same' threshold makes books almost the same
+// whereas a score below 'maybe_same' threshold makes them maybe the same.
+// Cyrillic and Greek strings are compared using their Latin transliteration
+// so that they match their transliterated version (this can be disabled using
+// `"transliterate": false` setting).
+//
+// Similarity thresholds and fields' weights (ISBN, Title, Authors, Publisher
+// and PublishedDate) can be tuned from a JSON file using `-comparator` flag of
+// `libro info` or `libro compare`. A field whose weight is 0 is not compared:
+//
+//	{
+//	  "thresholds": {"same": 0.9, "almost_same": 0.8, "maybe_same": 0.7},
+//	  "weights": {"ISBN": 3, "Title": 2, "Authors": 2, "Publisher": 1, "PublishedDate": 1}
+//	}
+//
+// # COVERS
+//
+// `libro cover` extracts the cover of an EPUB. Cover is located using EPUB3
+// 'cover-image' manifest property, EPUB2 'cover' meta or the first image of
+// the first spine item.
+//
+// `libro insert -cover` saves the book's cover and a thumbnail of it as JPEG
+// next to the inserted book, named after the book's file ('xXx.jpg' and
+// 'xXx.thumb.jpg') rather than 'cover.jpg' so that books sharing a folder do
+// not overwrite each other's cover. Failing to save the cover is reported as
+// a warning and does not prevent the book from being inserted.
+//
+// `libro check -cover` reports books without cover or whose cover is of low
+// resolution, has an unusual aspect ratio, is uselessly huge or is declared
+// but missing from the EPUB archive. Resolution and aspect ratio of SVG covers
+// are not checked.
+//
+// # TEXT
+//
+// `libro text` exports the text of an EPUB, following its reading order, as
+// plain text or as Markdown (`-markdown` flag). Chapters are separated by a
+// separation line.
+//
+// `libro text -stats` reports the number of words and characters of the
+// text, its estimated reading time and its estimated number of pages (see
+// `-words-per-minute` and `-words-per-page` flags).
+//
+// `libro info -estimate-pages` sets book's PageCount from its estimated
+// number of pages when no other source provides it.
+//
+// # FULL-TEXT SEARCH
+//
+// `libro grep "QUERY"` searches the content of the library's books and
+// reports the book, the chapter's file and an excerpt of the text for each
+// match. Matches are sorted by the sortable name of the books' first author.
+// Queries are made of words or of phrases enclosed in double quotes (like
+// `"down the rabbit hole"`) combined using AND (default), OR, NOT (or '-'
+// prefix) and parentheses. Words are case-folded and stripped from their
+// accents.
+//
+// Search relies on a full-text index stored in the library's root folder
+// ('.libro.index'). The index is built at first search (or when using
+// `-reindex` flag) then updated when a book is inserted (`libro insert`) or
+// removed (`libro remove`). Each book is indexed in its own file so that
+// inserting or removing a book only writes or deletes its file whereas
+// searching reads the whole index. Matches in books that have been moved or deleted
+// without using `libro` are skipped with a warning until the index is
+// rebuilt. Failing to add a book to the index is reported as a warning and
+// does not prevent the book from being inserted.
+//
 // # CHECKER
 //
 // `libro` can run different check to verify quality, completeness or conformity of
@@ -127,4 +303,37 @@
 //
 // `libro` relies on [EPUBcheck](https://www.w3.org/publishing/epubcheck/) tool
 // for conformity verification.
+//
+// `libro` can inspect EPUB's content for accessibility shortcomings (images
+// without alternate text, missing language declaration, skipped heading
+// levels, tables without headers, missing landmarks or page-list and missing
+// schema.org accessibility metadata) using `-accessibility` flag of `libro
+// check` sub-command. EPUB2 guide and NCX's pageList are accepted as
+// landmarks and page-list.
+//
+// `libro check` warns when book's Language is unusual for the registration
+// group (language area or country) of its ISBN. Built-in ISBN ranges are a
+// simplified subset covering the main registration groups only (English,
+// French, German, Japanese, Russian, Chinese, Czech, Polish, Spanish,
+// Portuguese, Italian, Dutch, Swedish and Korean ones) so that some ISBN can
+// be wrongly hyphenated or their registrant missed. The complete and
+// up-to-date ranges published by the International ISBN Agency
+// (RangeMessage.xml) can be used thanks to `-isbn-ranges` flag or built-in by
+// running `go generate ./book` before building `libro`. ISBN of unknown
+// registration groups are not hyphenated by `hyphenisbn` template function.
+//
+// `libro check -security` scans EPUB's HTML and CSS content for security
+// risks. Scripts are reported apart from other risks and grouped by
+// category: inline or external scripts, event handlers, javascript: URLs
+// (including in CSS or in iframes' srcdoc), scripted SVG and content documents
+// declared as scripted in the EPUB's manifest.
+//
+// `libro clean -security` produces a copy of an EPUB ('xXx.clean.epub' by
+// default, see `-output` flag) where the HTML and CSS content that `libro
+// check -security` reports as unsafe is removed: tags, attributes, style
+// declarations or rules that are not allowed are dropped and offending inline
+// styles are cleaned from their unsafe declarations. SVG and MathML content,
+// including SVG images, is kept but for its scripts, event handlers and
+// javascript: URLs and manifest's items are not declared as 'scripted'
+// anymore. Each removal is reported.
 package main
@@ -0,0 +1,571 @@
+// Package index provides a full-text inverted index of the content of the
+// books of a library. It supports phrase and boolean queries, terms are
+// case-folded and stripped from their accents so that queries do not need to
+// reproduce the exact spelling of the text.
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pirmd/libro/book"
+)
+
+// Filename is the name of the folder storing the index in the library's root
+// folder.
+const Filename = ".libro.index"
+
+// segmentExt is the extension of the files storing the index's segments.
+const segmentExt = ".seg"
+
+var (
+	// Verbose is the logger of index package that provides feedback of
+	// operation done on the index.
+	Verbose = log.New(io.Discard, log.Prefix(), log.Flags())
+
+	// SnippetSize is the number of words of the text surrounding a match
+	// that are displayed before and after it.
+	SnippetSize = 8
+
+	// errStop is used to interrupt the walk of a book's content.
+	errStop = errors.New("stop")
+)
+
+// Index is a full-text inverted index of the content of the books of a
+// library.
+//
+// Index is stored as one segment file per book so that adding or removing a
+// book only writes or deletes its own segment. Segments are only read when
+// searching, searching costs therefore reading the whole Index.
+type Index struct {
+	root string
+
+	// segments maps the path of indexed books (relative to the library's
+	// root) to their segment.
+	segments map[string]*segment
+
+	// chapters lists the indexed chapters by identifier.
+	chapters map[int]Chapter
+
+	// postings maps terms to the chapters they appear in and to their
+	// positions (in words) in these chapters.
+	postings map[string]map[int][]int
+
+	// nextID is the identifier of the next indexed chapter.
+	nextID int
+
+	// changed lists the books whose segment is to be written (or deleted if
+	// the book is not indexed anymore) by Save.
+	changed map[string]bool
+
+	// loaded is set once the segments of the Index have been read.
+	loaded bool
+
+	// rebuilt is set for an Index built from scratch, Save deletes the
+	// segments of the books it does not contain.
+	rebuilt bool
+}
+
+// segment is the part of the Index describing one book.
+type segment struct {
+	// Book is the path of the book (relative to the library's root).
+	Book string
+
+	// SortKey is the key used to sort the book's matches (see
+	// book.Book.SortKey).
+	SortKey string
+
+	// Chapters lists the name of the chapters' files of the book in reading
+	// order (chapters without text excluded).
+	Chapters []string
+
+	// Postings maps terms to the position of the chapters they appear in
+	// and to their positions (in words) in these chapters.
+	Postings map[string]map[int][]int
+
+	// ids are the identifiers of the book's chapters in the Index.
+	ids []int
+}
+
+// Chapter represents an indexed chapter of a book.
+type Chapter struct {
+	// Book is the path of the book (relative to the library's root).
+	Book string
+
+	// Name is the name of the chapter's file in the book.
+	Name string
+
+	// Rank is the position of the chapter in the book's reading order
+	// (chapters without text excluded).
+	Rank int
+}
+
+// Match represents a chapter that matches a query.
+type Match struct {
+	// Book is the path of the book (relative to the library's root).
+	Book string
+
+	// Chapter is the name of the chapter's file in the book.
+	Chapter string
+
+	// Snippet is an excerpt of the chapter's text around the match.
+	Snippet string
+
+	chapter Chapter
+	sortKey string
+	hit     hit
+}
+
+// New creates a new empty Index for the library located at root. Saving it
+// replaces any existing index of the library.
+func New(root string) *Index {
+	idx := newIndex(root)
+	idx.loaded, idx.rebuilt = true, true
+	return idx
+}
+
+func newIndex(root string) *Index {
+	return &Index{
+		root:     root,
+		segments: make(map[string]*segment),
+		chapters: make(map[int]Chapter),
+		postings: make(map[string]map[int][]int),
+		changed:  make(map[string]bool),
+	}
+}
+
+// Load opens the Index of the library located at root. Index's segments are
+// only read when searching. If the library has no index, Load returns an
+// error satisfying errors.Is(err, fs.ErrNotExist).
+func Load(root string) (*Index, error) {
+	fi, err := os.Stat(filepath.Join(root, Filename))
+	if err != nil {
+		return nil, err
+	}
+
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("fail to read index: '%s' is not a folder (rebuild it using -reindex)", Filename)
+	}
+
+	return newIndex(root), nil
+}
+
+// Save writes the segments of the books added to the Index and deletes the
+// ones of removed books.
+func (idx *Index) Save() error {
+	dir := filepath.Join(idx.root, Filename)
+
+	if idx.rebuilt {
+		// Index stored in a single file by previous versions is replaced.
+		if fi, err := os.Stat(dir); err == nil && !fi.IsDir() {
+			if err := os.Remove(dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	for path, isIndexed := range idx.changed {
+		if !isIndexed {
+			if err := os.Remove(segmentPath(dir, path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+
+		if err := idx.segments[path].save(segmentPath(dir, path)); err != nil {
+			return err
+		}
+	}
+	idx.changed = make(map[string]bool)
+
+	if idx.rebuilt {
+		if err := idx.removeStaleSegments(dir); err != nil {
+			return err
+		}
+		idx.rebuilt = false
+	}
+
+	return nil
+}
+
+// removeStaleSegments deletes segments of books that are not part of the
+// Index.
+func (idx *Index) removeStaleSegments(dir string) error {
+	indexed := make(map[string]bool, len(idx.segments))
+	for path := range idx.segments {
+		indexed[segmentPath(dir, path)] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !indexed[f] {
+			if err := os.Remove(f); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// load reads the segments of the Index. Segments of books that have been
+// added or removed since the Index was opened are ignored. Segments that
+// cannot be read are skipped with a warning.
+func (idx *Index) load() error {
+	if idx.loaded {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(idx.root, Filename, "*"+segmentExt))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		seg, err := loadSegment(f)
+		if err != nil {
+			Verbose.Printf("warn: fail to read index segment '%s': %v: skipped, index might be outdated (rebuild it using -reindex)", f, err)
+			continue
+		}
+
+		if _, changed := idx.changed[seg.Book]; changed {
+			continue
+		}
+		if _, known := idx.segments[seg.Book]; known {
+			continue
+		}
+		idx.merge(seg)
+	}
+
+	idx.loaded = true
+	return nil
+}
+
+// Add indexes the content of the book located at path (relative to the
+// library's root). A previously indexed version of the book is replaced.
+// Books' SortKey is recorded so that matches can be sorted without reading
+// books' metadata. If the book cannot be indexed, its previously indexed
+// version is removed.
+func (idx *Index) Add(path string) error {
+	b, err := book.NewFromFile(filepath.Join(idx.root, path))
+	if err != nil {
+		idx.Remove(path)
+		return err
+	}
+
+	seg := &segment{
+		Book:     path,
+		SortKey:  b.SortKey(),
+		Postings: make(map[string]map[int][]int),
+	}
+
+	if err := b.WalkText(func(name string, txt string) error {
+		rank := len(seg.Chapters)
+		seg.Chapters = append(seg.Chapters, name)
+
+		for pos, tok := range tokenize(txt) {
+			postings, exists := seg.Postings[tok.term]
+			if !exists {
+				postings = make(map[int][]int)
+				seg.Postings[tok.term] = postings
+			}
+			postings[rank] = append(postings[rank], pos)
+		}
+
+		return nil
+	}); err != nil {
+		idx.Remove(path)
+		return err
+	}
+
+	idx.remove(path)
+	idx.merge(seg)
+	idx.changed[path] = true
+	return nil
+}
+
+// Remove removes the book located at path (relative to the library's root)
+// from the Index.
+func (idx *Index) Remove(path string) {
+	idx.remove(path)
+	idx.changed[path] = false
+}
+
+// merge adds a book's segment to the Index's terms and chapters.
+func (idx *Index) merge(seg *segment) {
+	seg.ids = make([]int, len(seg.Chapters))
+	for rank, name := range seg.Chapters {
+		seg.ids[rank] = idx.nextID
+		idx.chapters[idx.nextID] = Chapter{Book: seg.Book, Name: name, Rank: rank}
+		idx.nextID++
+	}
+
+	for term, segPostings := range seg.Postings {
+		postings, exists := idx.postings[term]
+		if !exists {
+			postings = make(map[int][]int)
+			idx.postings[term] = postings
+		}
+
+		for rank, positions := range segPostings {
+			postings[seg.ids[rank]] = positions
+		}
+	}
+
+	idx.segments[seg.Book] = seg
+}
+
+// remove removes a book's segment from the Index's terms and chapters.
+func (idx *Index) remove(path string) {
+	seg, exists := idx.segments[path]
+	if !exists {
+		return
+	}
+
+	for _, id := range seg.ids {
+		delete(idx.chapters, id)
+	}
+
+	for term := range seg.Postings {
+		postings := idx.postings[term]
+		for _, id := range seg.ids {
+			delete(postings, id)
+		}
+
+		if len(postings) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+
+	delete(idx.segments, path)
+}
+
+// Search looks for the chapters matching query. Matches are sorted by book
+// (see book.SortByAuthor) then by chapter's position in the book's reading
+// order.
+// See Parse for the supported query syntax.
+func (idx *Index) Search(query string) ([]*Match, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := idx.load(); err != nil {
+		return nil, err
+	}
+
+	var matches []*Match
+	for id, h := range q.eval(idx) {
+		c := idx.chapters[id]
+		matches = append(matches, &Match{Book: c.Book, Chapter: c.Name, chapter: c, sortKey: idx.segments[c.Book].SortKey, hit: h})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].sortKey != matches[j].sortKey {
+			return matches[i].sortKey < matches[j].sortKey
+		}
+		if matches[i].Book != matches[j].Book {
+			return matches[i].Book < matches[j].Book
+		}
+		return matches[i].chapter.Rank < matches[j].chapter.Rank
+	})
+
+	return idx.fillSnippets(matches), nil
+}
+
+// save writes the segment to path, replacing any previous version.
+func (seg *segment) save(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := gob.NewEncoder(f).Encode(seg); err != nil {
+		f.Close()
+		return fmt.Errorf("fail to write index: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// loadSegment reads the segment stored at path.
+func loadSegment(path string) (*segment, error) {
+	//#nosec G304 -- path is built from the library's root that is explicitly supplied by end-user.
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seg := new(segment)
+	if err := gob.NewDecoder(f).Decode(seg); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+// segmentPath returns the location of the segment of the book located at
+// path in the index's folder dir.
+func segmentPath(dir string, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+segmentExt)
+}
+
+// fillSnippets extracts from books' content the excerpt of text surrounding
+// each match. Matches of books that cannot be read (like books that have been
+// moved or deleted without updating the index) are skipped with a warning.
+func (idx *Index) fillSnippets(matches []*Match) []*Match {
+	var filled []*Match
+	for i := 0; i < len(matches); {
+		path := matches[i].Book
+		j := i
+		for j < len(matches) && matches[j].Book == path {
+			j++
+		}
+		bookMatches := matches[i:j]
+		i = j
+
+		if err := idx.fillBookSnippets(path, bookMatches); err != nil {
+			Verbose.Printf("warn: fail to read '%s': %v: skipped, index might be outdated (rebuild it using -reindex)", path, err)
+			continue
+		}
+		filled = append(filled, bookMatches...)
+	}
+
+	return filled
+}
+
+// fillBookSnippets extracts the snippets of the matches of a book. Matches
+// are expected to be sorted by chapter's position in the book's reading
+// order.
+func (idx *Index) fillBookSnippets(path string, matches []*Match) error {
+	b := book.New()
+	b.Path = filepath.Join(idx.root, path)
+
+	// Book's existence is checked first as opening a missing EPUB is not
+	// reliably reported as an error.
+	if _, err := os.Stat(b.Path); err != nil {
+		return err
+	}
+
+	lastRank := matches[len(matches)-1].chapter.Rank
+	var rank, k int
+	err := b.WalkText(func(name string, txt string) error {
+		for ; k < len(matches) && matches[k].chapter.Rank == rank; k++ {
+			matches[k].Snippet = snippet(txt, matches[k].hit)
+		}
+
+		if rank++; rank > lastRank {
+			return errStop
+		}
+		return nil
+	})
+	if err != nil && err != errStop {
+		return err
+	}
+
+	return nil
+}
+
+// snippet returns the excerpt of a text surrounding a hit.
+func snippet(txt string, h hit) string {
+	tokens := tokenize(txt)
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	pos, length := h.pos, h.length
+	if pos < 0 || pos >= len(tokens) {
+		pos, length = 0, 0
+	}
+
+	start, end := pos-SnippetSize, pos+length+SnippetSize
+	if start < 0 {
+		start = 0
+	}
+	if end > len(tokens) {
+		end = len(tokens)
+	}
+
+	s := strings.Join(strings.Fields(txt[tokens[start].start:tokens[end-1].end]), " ")
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(tokens) {
+		s += "…"
+	}
+	return s
+}
+
+// token is a normalized term of a text and its location in the text.
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits a text into normalized terms. Terms are words normalized
+// using book.NormalizeString.
+func tokenize(txt string) []token {
+	var tokens []token
+
+	// normalized caches the terms of already met words as normalization is
+	// costly.
+	normalized := make(map[string][]string)
+	appendTerms := func(start, end int) {
+		word := txt[start:end]
+		terms, exists := normalized[word]
+		if !exists {
+			terms = strings.Fields(book.NormalizeString(word))
+			normalized[word] = terms
+		}
+
+		for _, term := range terms {
+			tokens = append(tokens, token{term: term, start: start, end: end})
+		}
+	}
+
+	start := -1
+	for i, r := range txt {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+
+		if start >= 0 {
+			appendTerms(start, i)
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		appendTerms(start, len(txt))
+	}
+
+	return tokens
+}
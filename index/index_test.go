@@ -0,0 +1,292 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pirmd/libro/util"
+)
+
+const (
+	testdata      = "../testdata" //Use test data of the main package
+	testdataBooks = testdata + "/books"
+)
+
+func TestTokenize(t *testing.T) {
+	in := "L'Été, à Paris — «Ça va?»"
+	want := []string{"l", "ete", "a", "paris", "ca", "va"}
+
+	var got []string
+	for _, tok := range tokenize(in) {
+		got = append(got, tok.term)
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Tokenizing '%s' failed.\nWant: %v\nGot : %v", in, want, got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		in    string
+		want  string
+		isErr bool
+	}{
+		{in: `rabbit`, want: `[rabbit]`},
+		{in: `"White Rabbit"`, want: `[white rabbit]`},
+		{in: `rabbit hole`, want: `[[rabbit] [hole]]`},
+		{in: `rabbit AND hole`, want: `[[rabbit] [hole]]`},
+		{in: `rabbit OR hatter`, want: `[[rabbit] [hatter]]`},
+		{in: `rabbit -hole`, want: `[[rabbit] {[hole]}]`},
+		{in: `rabbit NOT (hole OR "mad hatter")`, want: `[[rabbit] {[[hole] [mad hatter]]}]`},
+		{in: `Été`, want: `[ete]`},
+		{in: ``, isErr: true},
+		{in: `rabbit AND`, isErr: true},
+		{in: `(rabbit`, isErr: true},
+		{in: `rabbit)`, isErr: true},
+		{in: `"…"`, isErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := Parse(tc.in)
+		if tc.isErr {
+			if err == nil {
+				t.Errorf("Parsing '%s' should fail", tc.in)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Fail to parse '%s': %v", tc.in, err)
+			continue
+		}
+
+		if fmt.Sprint(got) != tc.want {
+			t.Errorf("Parsing '%s' failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"pg11.epub", "pg2707.epub"} {
+		if err := util.CopyFile(filepath.Join(root, name), filepath.Join(testdataBooks, name)); err != nil {
+			t.Fatalf("Fail to prepare library: %v", err)
+		}
+	}
+
+	if _, err := Load(root); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Loading a non-existing index should fail with fs.ErrNotExist: %v", err)
+	}
+
+	idx := New(root)
+	for _, name := range []string{"pg11.epub", "pg2707.epub"} {
+		if err := idx.Add(name); err != nil {
+			t.Fatalf("Fail to index %s: %v", name, err)
+		}
+	}
+
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Fail to save index: %v", err)
+	}
+
+	idx, err := Load(root)
+	if err != nil {
+		t.Fatalf("Fail to load index: %v", err)
+	}
+
+	testCases := []struct {
+		query string
+		want  []string
+	}{
+		{`"down the rabbit hole"`, []string{"pg11.epub"}},
+		{`"DOWN THE RABBIT-HOLE"`, []string{"pg11.epub"}},
+		{`"rabbit down the hole"`, nil},
+		{`rabbit AND hatter AND -dodo`, []string{"pg11.epub"}},
+		{`"white rabbit" OR "IONIANS of truly pure descent"`, []string{"pg11.epub", "pg2707.epub"}},
+	}
+
+	for _, tc := range testCases {
+		matches, err := idx.Search(tc.query)
+		if err != nil {
+			t.Errorf("Fail to search '%s': %v", tc.query, err)
+			continue
+		}
+
+		var got []string
+		for _, m := range matches {
+			if m.Snippet == "" {
+				t.Errorf("Match of '%s' in %s (%s) has no snippet", tc.query, m.Book, m.Chapter)
+			}
+			if !isInList(m.Book, got) {
+				got = append(got, m.Book)
+			}
+		}
+		sort.Strings(got)
+
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("Searching '%s' failed.\nWant: %v\nGot : %v", tc.query, tc.want, got)
+		}
+	}
+
+	t.Run("MovedBook", func(t *testing.T) {
+		if err := os.Rename(filepath.Join(root, "pg2707.epub"), filepath.Join(root, "moved.epub")); err != nil {
+			t.Fatalf("Fail to move book: %v", err)
+		}
+		defer os.Rename(filepath.Join(root, "moved.epub"), filepath.Join(root, "pg2707.epub"))
+
+		matches, err := idx.Search(`"white rabbit" OR "IONIANS of truly pure descent"`)
+		if err != nil {
+			t.Fatalf("Fail to search with an outdated index: %v", err)
+		}
+
+		for _, m := range matches {
+			if m.Book != "pg11.epub" {
+				t.Errorf("Moved book should be skipped: %+v", m)
+			}
+		}
+		if len(matches) == 0 {
+			t.Errorf("Matches of readable books should be kept")
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		idx.Remove("pg11.epub")
+
+		matches, err := idx.Search(`rabbit`)
+		if err != nil {
+			t.Fatalf("Fail to search 'rabbit': %v", err)
+		}
+
+		for _, m := range matches {
+			if m.Book == "pg11.epub" {
+				t.Errorf("Removed book is still found: %+v", m)
+			}
+		}
+	})
+}
+
+func TestIndexSegments(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"pg11.epub", "pg2707.epub"} {
+		if err := util.CopyFile(filepath.Join(root, name), filepath.Join(testdataBooks, name)); err != nil {
+			t.Fatalf("Fail to prepare library: %v", err)
+		}
+	}
+
+	// Index stored in a single file by previous versions is replaced.
+	if err := os.WriteFile(filepath.Join(root, Filename), []byte("old index"), 0o600); err != nil {
+		t.Fatalf("Fail to prepare old index: %v", err)
+	}
+	if _, err := Load(root); err == nil {
+		t.Errorf("Loading an old index should fail")
+	}
+
+	idx := New(root)
+	if err := idx.Add("pg11.epub"); err != nil {
+		t.Fatalf("Fail to index pg11.epub: %v", err)
+	}
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Fail to save index: %v", err)
+	}
+
+	segments := func() []string {
+		files, err := filepath.Glob(filepath.Join(root, Filename, "*"+segmentExt))
+		if err != nil {
+			t.Fatalf("Fail to list index segments: %v", err)
+		}
+		return files
+	}
+
+	if got := segments(); len(got) != 1 {
+		t.Errorf("Index should have one segment per book.\nWant: 1 segment\nGot : %v", got)
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		idx, err := Load(root)
+		if err != nil {
+			t.Fatalf("Fail to load index: %v", err)
+		}
+
+		if err := idx.Add("pg2707.epub"); err != nil {
+			t.Fatalf("Fail to index pg2707.epub: %v", err)
+		}
+		if idx.loaded {
+			t.Errorf("Adding a book should not read the other books' segments")
+		}
+
+		if err := idx.Save(); err != nil {
+			t.Fatalf("Fail to save index: %v", err)
+		}
+
+		if got := segments(); len(got) != 2 {
+			t.Errorf("Adding a book should add its segment.\nWant: 2 segments\nGot : %v", got)
+		}
+
+		matches, err := idx.Search(`"down the rabbit hole" OR "Ionians of truly pure descent"`)
+		if err != nil {
+			t.Fatalf("Fail to search: %v", err)
+		}
+
+		// Books are sorted by their author: Carroll before Herodotus.
+		var got []string
+		for _, m := range matches {
+			if !isInList(m.Book, got) {
+				got = append(got, m.Book)
+			}
+		}
+		if want := []string{"pg11.epub", "pg2707.epub"}; fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Searching updated index failed.\nWant: %v\nGot : %v", want, got)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		idx, err := Load(root)
+		if err != nil {
+			t.Fatalf("Fail to load index: %v", err)
+		}
+
+		idx.Remove("pg11.epub")
+		if err := idx.Save(); err != nil {
+			t.Fatalf("Fail to save index: %v", err)
+		}
+
+		if got := segments(); len(got) != 1 {
+			t.Errorf("Removing a book should delete its segment.\nWant: 1 segment\nGot : %v", got)
+		}
+
+		matches, err := idx.Search(`rabbit OR Ionians`)
+		if err != nil {
+			t.Fatalf("Fail to search: %v", err)
+		}
+		for _, m := range matches {
+			if m.Book != "pg2707.epub" {
+				t.Errorf("Removed book is still found: %+v", m)
+			}
+		}
+	})
+
+	t.Run("Rebuild", func(t *testing.T) {
+		if err := New(root).Save(); err != nil {
+			t.Fatalf("Fail to save index: %v", err)
+		}
+
+		if got := segments(); len(got) != 0 {
+			t.Errorf("Rebuilding index should delete segments of books that are not indexed anymore.\nWant: no segment\nGot : %v", got)
+		}
+	})
+}
+
+func isInList(s string, list []string) bool {
+	for _, l := range list {
+		if s == l {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,266 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// hit is the location of the first occurrence of a query's terms in a
+// chapter. pos is negative for chapters that match a query without
+// containing any of its terms (like NOT queries).
+type hit struct {
+	pos, length int
+}
+
+// Query is a parsed full-text query.
+type Query interface {
+	// eval returns the chapters of the Index matching the Query.
+	eval(idx *Index) map[int]hit
+}
+
+type (
+	phraseQuery []string
+	andQuery    [2]Query
+	orQuery     [2]Query
+	notQuery    struct{ Query }
+)
+
+// Parse parses a full-text query. A query is made of words or of phrases
+// enclosed in double quotes (like `"to be or not to be"`) that must be found
+// in the same chapter. Words and phrases can be combined using AND
+// (default when no operator is given), OR and NOT (or '-' prefix) boolean
+// operators and grouped using parentheses.
+// Words are case-folded and stripped from their accents before searching.
+func Parse(query string) (Query, error) {
+	p := &parser{tokens: lexQuery(query)}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("empty query")
+	}
+
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in query", p.tokens[p.pos])
+	}
+
+	return q, nil
+}
+
+// parser is a recursive descent parser of full-text queries.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// parseOr parses: and ("OR" and)*
+func (p *parser) parseOr() (Query, error) {
+	q, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		q = orQuery{q, right}
+	}
+
+	return q, nil
+}
+
+// parseAnd parses: unary (["AND"] unary)*
+func (p *parser) parseAnd() (Query, error) {
+	q, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch tok := p.peek(); tok {
+		case "", "OR", ")":
+			return q, nil
+
+		case "AND":
+			p.next()
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		q = andQuery{q, right}
+	}
+}
+
+// parseUnary parses: ("NOT" | "-") unary | primary
+func (p *parser) parseUnary() (Query, error) {
+	if tok := p.peek(); tok == "NOT" || tok == "-" {
+		p.next()
+		q, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notQuery{q}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" query ")" | phrase | word
+func (p *parser) parsePrimary() (Query, error) {
+	switch tok := p.next(); tok {
+	case "":
+		return nil, errors.New("unexpected end of query")
+
+	case "(":
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing ')' in query")
+		}
+		return q, nil
+
+	case ")", "AND", "OR":
+		return nil, fmt.Errorf("unexpected '%s' in query", tok)
+
+	default:
+		var terms phraseQuery
+		for _, t := range tokenize(strings.Trim(tok, `"`)) {
+			terms = append(terms, t.term)
+		}
+
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("'%s' contains no searchable word", tok)
+		}
+		return terms, nil
+	}
+}
+
+// lexQuery splits a query into words, phrases (kept with their enclosing
+// double quotes), parentheses and '-' prefixes.
+func lexQuery(query string) []string {
+	var tokens []string
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			continue
+
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+
+		case r == '-' && (i == 0 || unicode.IsSpace(runes[i-1]) || runes[i-1] == '('):
+			tokens = append(tokens, "-")
+
+		case r == '"':
+			j := i + 1
+			for j < len(runes) && runes[j] != '"' {
+				j++
+			}
+			if j == len(runes) {
+				j--
+			}
+			tokens = append(tokens, `"`+string(runes[i+1:j+1]))
+			i = j
+
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsSpace(runes[j]) && runes[j] != '(' && runes[j] != ')' && runes[j] != '"' {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j - 1
+		}
+	}
+
+	return tokens
+}
+
+// eval looks for the chapters where the phrase's terms appear in sequence.
+func (q phraseQuery) eval(idx *Index) map[int]hit {
+	res := make(map[int]hit)
+
+	for id, positions := range idx.postings[q[0]] {
+	nextPosition:
+		for _, pos := range positions {
+			for k, term := range q[1:] {
+				if !hasPosition(idx.postings[term][id], pos+k+1) {
+					continue nextPosition
+				}
+			}
+
+			res[id] = hit{pos: pos, length: len(q)}
+			break
+		}
+	}
+
+	return res
+}
+
+func (q andQuery) eval(idx *Index) map[int]hit {
+	left, right := q[0].eval(idx), q[1].eval(idx)
+
+	res := make(map[int]hit)
+	for id, h := range left {
+		if hr, exists := right[id]; exists {
+			if h.pos < 0 {
+				h = hr
+			}
+			res[id] = h
+		}
+	}
+	return res
+}
+
+func (q orQuery) eval(idx *Index) map[int]hit {
+	res := q[0].eval(idx)
+	for id, h := range q[1].eval(idx) {
+		if hl, exists := res[id]; !exists || hl.pos < 0 {
+			res[id] = h
+		}
+	}
+	return res
+}
+
+func (q notQuery) eval(idx *Index) map[int]hit {
+	excluded := q.Query.eval(idx)
+
+	res := make(map[int]hit)
+	for id := range idx.chapters {
+		if _, exists := excluded[id]; !exists {
+			res[id] = hit{pos: -1}
+		}
+	}
+	return res
+}
+
+// hasPosition checks whether a sorted list of positions contains pos.
+func hasPosition(positions []int, pos int) bool {
+	i := sort.SearchInts(positions, pos)
+	return i < len(positions) && positions[i] == pos
+}
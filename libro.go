@@ -4,19 +4,59 @@ import (
 	"embed"
 
 	"bytes"
+	"errors"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/pirmd/libro/book"
+	"github.com/pirmd/libro/index"
 	"github.com/pirmd/libro/util"
 )
 
 //go:embed templates/name/*
 var nameTmplDir embed.FS
 
+// bookFuncMap provides functions to manipulate Book's information in
+// template.Funcmap's format:
+//   - langname: get the name of a language in the given locale ('self' to
+//     get the name in the language itself)
+//   - baselang: get the language code without script or region
+//   - sortname: get the sortable form of a person's name ("Surname, Forename")
+//   - translit: transliterate Cyrillic and Greek letters to Latin ones
+//   - hyphenisbn: get the hyphenated form of an ISBN
+//   - isbngroup: get the language area or country of an ISBN
+//   - isbnregistrant: get the registrant (publisher) prefix of an ISBN
+//
+// ISBN functions do not fail for ISBN whose registration group is not known
+// (built-in ISBN ranges only cover the main registration groups): ISBN is
+// returned as-is by hyphenisbn, isbngroup and isbnregistrant return "".
+var bookFuncMap = template.FuncMap{
+	"langname": book.LanguageName,
+	"baselang": book.BaseLanguage,
+	"sortname": book.SortName,
+	"translit": book.Transliterate,
+
+	"hyphenisbn": func(isbn string) string {
+		if hyphenated, err := book.HyphenateISBN(isbn); err == nil {
+			return hyphenated
+		}
+		return isbn
+	},
+	"isbngroup": func(isbn string) string {
+		group, _ := book.ISBNGroup(isbn)
+		return group
+	},
+	"isbnregistrant": func(isbn string) string {
+		registrant, _ := book.ISBNRegistrant(isbn)
+		return registrant
+	},
+}
+
 // Libro represents a collection of media and its associated management
 // facilities.
 type Libro struct {
@@ -49,12 +89,27 @@ type Libro struct {
 	// files location in the collection based on their metadata.
 	// Default to nil (keep item location as-is)
 	PathTmpl *template.Template
+
+	// SaveCover, if set, saves the book's cover and a thumbnail of it next to
+	// the book's file when inserting a new book in the collection.
+	// Default to false (do not save cover)
+	SaveCover bool
+
+	// ThumbnailSize defines the maximum size (width or height) in pixels of
+	// cover's thumbnails.
+	// Default to 200
+	ThumbnailSize int
+
+	// EstimatePageCount, if set, estimates book's PageCount from the number
+	// of words of its content when no source provides it.
+	// Default to false (do not estimate PageCount)
+	EstimatePageCount bool
 }
 
 // NewLibro creates a new Libro.
 func NewLibro() *Libro {
 	tmpl := template.New("location").Option("missingkey=error")
-	tmpl = tmpl.Funcs(util.StringsFuncMap).Funcs(util.FilepathFuncMap).Funcs(util.TmplFuncMap(tmpl))
+	tmpl = tmpl.Funcs(util.StringsFuncMap).Funcs(util.FilepathFuncMap).Funcs(bookFuncMap).Funcs(util.TmplFuncMap(tmpl))
 	tmpl = template.Must(tmpl.ParseFS(nameTmplDir, "templates/name/*"))
 
 	return &Libro{
@@ -63,6 +118,7 @@ func NewLibro() *Libro {
 		Debug:            log.New(io.Discard, "debug:", 0),
 		PathTmpl:         template.Must(tmpl.Parse(`{{template "fullname.gotmpl" .}}`)),
 		MaxSearchResults: 3,
+		ThumbnailSize:    200,
 	}
 }
 
@@ -74,6 +130,11 @@ func (lib *Libro) Read(path string) (*book.Book, error) {
 		return nil, err
 	}
 
+	// searchPublisher is the publisher used to search Googlebooks. Publisher
+	// guessed from book's content is not used: copyright pages often mention
+	// the original or a former publisher of the work that prevents any match.
+	searchPublisher := b.Publisher
+
 	if lib.UseGuesser {
 		lib.Verbose.Print("Clean book's metadata")
 		if err := b.CleanMetadata(); err != nil {
@@ -85,15 +146,26 @@ func (lib *Libro) Read(path string) (*book.Book, error) {
 			return nil, err
 		}
 
+		lib.Verbose.Print("Guess information from book's directory layout")
+		if err := lib.guessFromDirLayout(b); err != nil {
+			return nil, err
+		}
+		searchPublisher = b.Publisher
+
 		lib.Verbose.Print("Guess information from book's content")
 		if err := lib.guessFromContent(b); err != nil {
 			return nil, err
 		}
+
+		lib.Verbose.Print("Guess language from book's content")
+		if err := b.GuessLanguageFromContent(); err != nil {
+			return nil, err
+		}
 	}
 
 	if lib.UseGooglebooks {
 		lib.Verbose.Print("Get information from Googlebooks")
-		if err := lib.searchOnGooglebooks(b); err != nil {
+		if err := lib.searchOnGooglebooks(b, searchPublisher); err != nil {
 			return nil, err
 		}
 	}
@@ -105,6 +177,13 @@ func (lib *Libro) Read(path string) (*book.Book, error) {
 		}
 	}
 
+	if lib.EstimatePageCount {
+		lib.Verbose.Print("Estimate book's page count from its content")
+		if err := b.EstimatePageCount(); err != nil {
+			return nil, err
+		}
+	}
+
 	return b, nil
 }
 
@@ -153,11 +232,154 @@ func (lib *Libro) Create(b *book.Book) error {
 		return err
 	}
 
+	if lib.SaveCover {
+		if err := lib.saveCover(b, dst); err != nil {
+			b.ReportWarning("fail to save cover: %v", err)
+		}
+	}
+
+	if err := lib.updateIndex(dst, false); err != nil {
+		b.ReportWarning("fail to add book to full-text index: %v", err)
+	}
+
 	b.Path = path
 
 	return nil
 }
 
+// Remove deletes a book from Libro's collection, as well as its cover and
+// thumbnail if any.
+// Location of the book is relative to the Libro's root folder.
+func (lib *Libro) Remove(path string) error {
+	dst := lib.fullpath(path)
+	lib.Verbose.Printf("Remove book '%s' from library in '%s'", dst, lib.Root)
+
+	if err := removeBookFiles(dst); err != nil {
+		return err
+	}
+
+	return lib.updateIndex(dst, true)
+}
+
+// removeBookFiles deletes a book's file as well as its cover and thumbnail if
+// any.
+func removeBookFiles(path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	basename := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, img := range []string{basename + ".jpg", basename + ".thumb.jpg"} {
+		if err := os.Remove(img); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reindex builds Libro's full-text index from the content of all the books of
+// the collection. Any previous index is replaced. Books whose content cannot
+// be read are not indexed.
+func (lib *Libro) Reindex() error {
+	lib.Verbose.Printf("Build full-text index of library in '%s'", lib.Root)
+
+	idx := index.New(lib.Root)
+	if err := lib.Walk(func(b *book.Book) error {
+		lib.Debug.Printf("index book '%s'", b.Path)
+		if err := idx.Add(b.Path); err != nil {
+			lib.Verbose.Printf("warn: fail to index '%s': %v: skipped", b.Path, err)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return idx.Save()
+}
+
+// Grep searches the content of Libro's collection for the chapters matching
+// query (see index.Parse for query's syntax). Libro's full-text index is
+// built if it does not exist yet.
+func (lib *Libro) Grep(query string) ([]*index.Match, error) {
+	idx, err := index.Load(lib.Root)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := lib.Reindex(); err != nil {
+			return nil, err
+		}
+		idx, err = index.Load(lib.Root)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.Search(query)
+}
+
+// updateIndex adds (or removes) the book located at dst to (from) Libro's
+// full-text index. Nothing is done if the index does not exist yet, it will
+// be built at first search.
+func (lib *Libro) updateIndex(dst string, remove bool) error {
+	idx, err := index.Load(lib.Root)
+	if errors.Is(err, fs.ErrNotExist) {
+		lib.Debug.Printf("no full-text index in '%s'", lib.Root)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	path, err := filepath.Rel(lib.Root, dst)
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		lib.Verbose.Printf("remove '%s' from full-text index", path)
+		idx.Remove(path)
+	} else {
+		lib.Verbose.Printf("add '%s' to full-text index", path)
+		if err := idx.Add(path); err != nil {
+			return err
+		}
+	}
+
+	return idx.Save()
+}
+
+// Walk walks Libro's collection and calls fn for each EPUB found in it.
+// Books' information are read from their metadata only and their Path is
+// relative to Libro's Root. Unreadable files or EPUBs are reported and
+// skipped.
+func (lib *Libro) Walk(fn func(b *book.Book) error) error {
+	return filepath.WalkDir(lib.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			lib.Verbose.Printf("warn: fail to access '%s': %v: skipped", path, err)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".epub") {
+			return nil
+		}
+
+		lib.Debug.Printf("read book '%s'", path)
+		b, err := book.NewFromFile(path)
+		if err != nil {
+			lib.Verbose.Printf("warn: fail to read '%s': %v: skipped", path, err)
+			return nil
+		}
+
+		if b.Path, err = filepath.Rel(lib.Root, path); err != nil {
+			return err
+		}
+
+		return fn(b)
+	})
+}
+
 // fullpath returns the full path to interact with Libro's collection. If
 // path is relative, fullpath returns its full location inside Libro's
 // root folder.  If path is absolute, fullpath returns its "clean"
@@ -171,6 +393,38 @@ func (lib *Libro) fullpath(path string) string {
 	return filepath.Join(lib.Root, filepath.Clean("/"+path))
 }
 
+// saveCover saves book's cover and its thumbnail as JPEG images next to the
+// book's file. Cover (respectively thumbnail) is named after the book's file
+// using '.jpg' (respectively '.thumb.jpg') extension.
+func (lib *Libro) saveCover(b *book.Book, bookPath string) error {
+	img, _, err := b.Cover()
+	if err == book.ErrNoCover {
+		b.ReportWarning("book has no cover.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	basename := strings.TrimSuffix(bookPath, filepath.Ext(bookPath))
+
+	lib.Verbose.Printf("save cover to '%s.jpg'", basename)
+	cover := new(bytes.Buffer)
+	if err := util.ToJPEG(cover, img, 0); err != nil {
+		return err
+	}
+	if err := util.WriteFile(basename+".jpg", cover); err != nil {
+		return err
+	}
+
+	lib.Verbose.Printf("save cover's thumbnail to '%s.thumb.jpg'", basename)
+	thumb := new(bytes.Buffer)
+	if err := util.ToJPEG(thumb, img, lib.ThumbnailSize); err != nil {
+		return err
+	}
+	return util.WriteFile(basename+".thumb.jpg", thumb)
+}
+
 func (lib *Libro) guessFromFilename(b *book.Book) error {
 	guessedBook, err := book.NewFromFilename(b.Path)
 	if err != nil {
@@ -182,6 +436,26 @@ func (lib *Libro) guessFromFilename(b *book.Book) error {
 		return nil
 	}
 
+	lib.completeFromGuessedPath(b, guessedBook)
+	return nil
+}
+
+func (lib *Libro) guessFromDirLayout(b *book.Book) error {
+	guessedBook, err := book.NewFromDirLayout(b.Path)
+	if err != nil {
+		return err
+	}
+
+	if guessedBook == nil {
+		lib.Debug.Print("no relevant information found from directory layout")
+		return nil
+	}
+
+	lib.completeFromGuessedPath(b, guessedBook)
+	return nil
+}
+
+func (lib *Libro) completeFromGuessedPath(b *book.Book, guessedBook *book.Book) {
 	lib.Debug.Print("verify that guessed information is consistent with current book's Metadata before merging")
 	switch lvl, rational := b.CompareWith(guessedBook); lvl {
 	case book.AreTheSame, book.AreAlmostTheSame, book.AreNotComparable:
@@ -191,8 +465,6 @@ func (lib *Libro) guessFromFilename(b *book.Book) error {
 	default:
 		lib.Debug.Printf("information are %s because %s. Do nothing.", lvl, rational)
 	}
-
-	return nil
 }
 
 func (lib *Libro) guessFromContent(b *book.Book) error {
@@ -222,8 +494,11 @@ func (lib *Libro) guessFromContent(b *book.Book) error {
 	return nil
 }
 
-func (lib *Libro) searchOnGooglebooks(b *book.Book) error {
-	matches, err := b.SearchOnGooglebooks(lib.MaxSearchResults)
+func (lib *Libro) searchOnGooglebooks(b *book.Book, publisher string) error {
+	query := *b
+	query.Publisher = publisher
+
+	matches, err := query.SearchOnGooglebooks(lib.MaxSearchResults)
 	if err != nil {
 		return err
 	}
@@ -233,13 +508,7 @@ func (lib *Libro) searchOnGooglebooks(b *book.Book) error {
 		return nil
 	}
 
-	bestMatch := matches[0]
-	for _, m := range matches {
-		if m.ISBN == b.ISBN {
-			bestMatch = m
-			break
-		}
-	}
+	bestMatch := bestGooglebooksMatch(b, matches)
 
 	lib.Debug.Print("verify that guessed information is consistent with current one before merging")
 	switch lvl, rational := b.CompareWith(bestMatch); lvl {
@@ -263,3 +532,27 @@ func (lib *Libro) searchOnGooglebooks(b *book.Book) error {
 
 	return nil
 }
+
+// bestGooglebooksMatch selects among Googlebooks' matches the one whose ISBN
+// is the book's one, otherwise the one that shares an ISBN with the book (like
+// the print edition of an ebook), otherwise the most relevant one.
+func bestGooglebooksMatch(b *book.Book, matches []*book.Book) *book.Book {
+	for _, m := range matches {
+		if m.ISBN == b.ISBN {
+			return m
+		}
+	}
+
+	for _, m := range matches {
+		if b.HasISBN(m.ISBN) || m.HasISBN(b.ISBN) {
+			return m
+		}
+		for _, isbn := range m.AlternateISBN {
+			if b.HasISBN(isbn) {
+				return m
+			}
+		}
+	}
+
+	return matches[0]
+}
@@ -2,11 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/pirmd/libro/book"
+	"github.com/pirmd/libro/index"
 
 	"github.com/pirmd/verify"
 )
@@ -193,4 +197,133 @@ func TestLibroCreate(t *testing.T) {
 			t.Fatalf("Library' final state is not as expected:\n%v", failure)
 		}
 	})
+
+	t.Run("WithCover", func(t *testing.T) {
+		library := newTestLibro(t)
+		library.SaveCover = true
+
+		for _, tc := range testCases {
+			b, err := library.Read(tc)
+			if err != nil {
+				t.Errorf("Fail to read information for %s: %v", tc, err)
+			}
+
+			if err := library.Create(b); err != nil {
+				t.Errorf("Fail to create book for %#v: %v", b, err)
+			}
+
+		}
+
+		got, err := library.List()
+		if err != nil {
+			t.Fatalf("Fail to read library's status: %v", err)
+		}
+
+		if failure := verify.MatchGolden(t.Name(), strings.Join(got, "\n")); failure != nil {
+			t.Fatalf("Library' final state is not as expected:\n%v", failure)
+		}
+	})
+}
+
+func TestLibroCreateWithBrokenIndex(t *testing.T) {
+	library := newTestLibro(t)
+	if err := os.WriteFile(filepath.Join(library.Libro.Root, index.Filename), []byte("not an index"), 0o600); err != nil {
+		t.Fatalf("Fail to create broken index: %v", err)
+	}
+
+	b, err := library.Read(filepath.Join(testdataBooks, "pg11.epub"))
+	if err != nil {
+		t.Fatalf("Fail to read information for pg11.epub: %v", err)
+	}
+
+	if err := library.Create(b); err != nil {
+		t.Fatalf("Creating book with a broken index should not fail: %v", err)
+	}
+
+	if len(b.Report.Warnings) == 0 {
+		t.Errorf("Failing to index book should be reported as a warning")
+	}
+
+	got, err := library.List()
+	if err != nil {
+		t.Fatalf("Fail to read library's status: %v", err)
+	}
+	if want := []string{index.Filename, b.Path}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Book should be kept when indexing fails.\nWant: %v\nGot : %v", want, got)
+	}
+}
+
+func TestLibroWalkWithUnreadableBook(t *testing.T) {
+	library := newTestLibro(t)
+
+	b, err := library.Read(filepath.Join(testdataBooks, "pg11.epub"))
+	if err != nil {
+		t.Fatalf("Fail to read information for pg11.epub: %v", err)
+	}
+	if err := library.Create(b); err != nil {
+		t.Fatalf("Fail to add pg11.epub to library: %v", err)
+	}
+	want := []string{b.Path}
+
+	if err := os.WriteFile(filepath.Join(library.Libro.Root, "broken.epub"), []byte("not an epub"), 0o600); err != nil {
+		t.Fatalf("Fail to create broken EPUB: %v", err)
+	}
+
+	var got []string
+	if err := library.Walk(func(b *book.Book) error {
+		got = append(got, b.Path)
+		return nil
+	}); err != nil {
+		t.Fatalf("Walking library with an unreadable book should not fail: %v", err)
+	}
+
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("Walking library failed.\nWant: %v\nGot : %v", want, got)
+	}
+}
+
+func TestNameTemplates(t *testing.T) {
+	library := newTestLibro(t)
+
+	b := &book.Book{
+		Path:     "alice.epub",
+		Title:    "Alice no País das Maravilhas",
+		Authors:  []string{"Lewis Carroll"},
+		Language: "pt-BR",
+	}
+
+	got := new(strings.Builder)
+	if err := library.PathTmpl.ExecuteTemplate(got, "fullname.gotmpl", b); err != nil {
+		t.Fatalf("Fail to execute fullname template: %v", err)
+	}
+
+	if want := "Lewis Carroll - Alice no País das Maravilhas [PT].epub"; got.String() != want {
+		t.Errorf("Naming book failed.\nWant: %v\nGot : %v", want, got)
+	}
+}
+
+func TestBookFuncMapISBN(t *testing.T) {
+	tmpl := template.Must(template.New("isbn").Funcs(bookFuncMap).Parse(`{{hyphenisbn .}}|{{isbngroup .}}|{{isbnregistrant .}}`))
+
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"9782070368228", "978-2-07-036822-8|French language|978-2-07"},
+		// Registration group 978-950 is not part of the built-in ISBN ranges.
+		{"9789500000000", "9789500000000||"},
+		{"not an ISBN", "not an ISBN||"},
+	}
+
+	for _, tc := range testCases {
+		got := new(strings.Builder)
+		if err := tmpl.Execute(got, tc.in); err != nil {
+			t.Errorf("Fail to execute ISBN functions for %s: %v", tc.in, err)
+			continue
+		}
+
+		if got.String() != tc.want {
+			t.Errorf("ISBN functions for %s failed.\nWant: %v\nGot : %v", tc.in, tc.want, got)
+		}
+	}
 }
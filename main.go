@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"embed"
 	"encoding/json"
 	"flag"
@@ -9,10 +10,12 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/pirmd/libro/book"
+	"github.com/pirmd/libro/index"
 	"github.com/pirmd/libro/util"
 )
 
@@ -50,7 +53,7 @@ type App struct {
 // NewApp creates a new App
 func NewApp() *App {
 	tmpl := template.New("formatter").Option("missingkey=error")
-	tmpl = tmpl.Funcs(util.SerializationFuncMap).Funcs(util.StringsFuncMap)
+	tmpl = tmpl.Funcs(util.SerializationFuncMap).Funcs(util.StringsFuncMap).Funcs(bookFuncMap)
 	tmpl = template.Must(tmpl.ParseFS(bookTmplDir, "templates/book/*.gotmpl"))
 
 	app := &App{
@@ -68,6 +71,7 @@ func NewApp() *App {
 
 	app.Library.Verbose, app.Library.Debug = app.Verbose, app.Debug
 	book.Verbose, book.Debug = app.Verbose, app.Debug
+	index.Verbose = app.Verbose
 
 	return app
 }
@@ -81,7 +85,15 @@ func (app *App) Run(args []string) error {
 		fmt.Fprintf(fs.Output(), "Commands:\n")
 		fmt.Fprintf(fs.Output(), "    info       retrieve information from an EPUB\n")
 		fmt.Fprintf(fs.Output(), "    insert     insert an EPUB into the library\n")
+		fmt.Fprintf(fs.Output(), "    remove     remove an EPUB from the library\n")
 		fmt.Fprintf(fs.Output(), "    edit       edit information about an EPUB\n")
+		fmt.Fprintf(fs.Output(), "    clean      produce a cleaned copy of an EPUB\n")
+		fmt.Fprintf(fs.Output(), "    cover      extract the cover of an EPUB\n")
+		fmt.Fprintf(fs.Output(), "    text       export the text of an EPUB and its statistics\n")
+		fmt.Fprintf(fs.Output(), "    authors    list authors found in the library\n")
+		fmt.Fprintf(fs.Output(), "    series     report series found in the library and their missing books\n")
+		fmt.Fprintf(fs.Output(), "    grep       search the content of the library's books\n")
+		fmt.Fprintf(fs.Output(), "    compare    explain the similarity of two books\n")
 		fmt.Fprintf(fs.Output(), "    version    print %s version\n", myname)
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
@@ -107,12 +119,36 @@ func (app *App) Run(args []string) error {
 	case "insert", "add":
 		return app.RunInsertSubcmd(fs.Args()[1:])
 
+	case "remove", "rm":
+		return app.RunRemoveSubcmd(fs.Args()[1:])
+
 	case "check":
 		return app.RunCheckSubcmd(fs.Args()[1:])
 
 	case "edit":
 		return app.RunEditSubcmd(fs.Args()[1:])
 
+	case "clean":
+		return app.RunCleanSubcmd(fs.Args()[1:])
+
+	case "cover":
+		return app.RunCoverSubcmd(fs.Args()[1:])
+
+	case "text":
+		return app.RunTextSubcmd(fs.Args()[1:])
+
+	case "authors":
+		return app.RunAuthorsSubcmd(fs.Args()[1:])
+
+	case "series":
+		return app.RunSeriesSubcmd(fs.Args()[1:])
+
+	case "grep":
+		return app.RunGrepSubcmd(fs.Args()[1:])
+
+	case "compare":
+		return app.RunCompareSubcmd(fs.Args()[1:])
+
 	default:
 		return fmt.Errorf("'%[1]s %s' unknown command\nRun %[1]s -help", fs.Name(), cmd)
 	}
@@ -130,6 +166,15 @@ func (app *App) RunInfoSubcmd(args []string) error {
 
 	fs.BoolVar(&app.Library.UseGuesser, "use-guesser", false, "completes book's metadata by guessing lacking information from book's filename and title")
 	fs.BoolVar(&app.Library.UseGooglebooks, "use-googlebooks", false, "completes book's metadata by searching lacking information from Googlebooks")
+	fs.Func("dir-layout", "adds a directory layout like '{Authors}/{Series}/{SeriesIndex} - {Title}' to guess book's metadata from its parent folders. Can be repeated, layouts are tried in the given order (requires -use-guesser)", book.AddDirLayout)
+	fs.Func("guesser-rules", "loads user-defined guesser and cleaner rules from a JSON file. User-defined rules take precedence over built-in ones (requires -use-guesser)", book.LoadRules)
+	fs.Func("authority", "loads authors' canonical names, aliases and pseudonyms from a JSON authority file", book.LoadAuthority)
+	fs.Func("series-registry", "loads series' canonical names, aliases and length from a JSON file (requires -use-guesser)", book.LoadSeries)
+	fs.Func("comparator", "loads books' comparison thresholds and weights from a JSON file", book.LoadComparator)
+	fs.Func("isbn-ranges", "loads ISBN ranges from the International ISBN Agency's RangeMessage XML file instead of the built-in ones", book.LoadISBNRanges)
+	fs.BoolVar(&book.MarkdownDescription, "markdown-description", false, "keeps book's description formatting (paragraphs, lists, emphasis...) as Markdown instead of plain text")
+	fs.BoolVar(&app.Library.EstimatePageCount, "estimate-pages", false, "estimates book's page count from its number of words when it is not known")
+	fs.IntVar(&book.WordsPerPage, "words-per-page", book.WordsPerPage, "average number of words of a page used to estimate book's page count")
 
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
@@ -166,6 +211,8 @@ func (app *App) RunInsertSubcmd(args []string) error {
 	fs.StringVar(&app.Library.Root, "root", app.Library.Root, "root folder where the books library is to be found")
 	fs.Var(util.NewGoTemplate(app.Library.PathTmpl), "rename", "sets filename format using golang text/template")
 	fs.Var(util.NewGoTemplateFS(app.Library.PathTmpl), "rename-tmpl", "loads user-defined filename template(s) from golang text/template definition files")
+	fs.BoolVar(&app.Library.SaveCover, "cover", false, "saves book's cover and its thumbnail as JPEG next to the book")
+	fs.IntVar(&app.Library.ThumbnailSize, "thumbnail-size", app.Library.ThumbnailSize, "maximum width or height in pixels of cover's thumbnail")
 
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
@@ -201,6 +248,34 @@ func (app *App) RunInsertSubcmd(args []string) error {
 	return nil
 }
 
+// RunRemoveSubcmd executes the "remove" sub-command.
+func (app *App) RunRemoveSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" remove", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] FILENAME\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "FILENAME is relative to the library's root folder.\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&app.Library.Root, "root", app.Library.Root, "root folder where the books library is to be found")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+
+	if err := app.Library.Remove(fs.Arg(0)); err != nil {
+		return fmt.Errorf("fail to remove book: %v", err)
+	}
+
+	return nil
+}
+
 // RunEditSubcmd executes the "edit" sub-command.
 func (app *App) RunEditSubcmd(args []string) error {
 	fs := flag.NewFlagSet(myname+" edit", flag.ExitOnError)
@@ -230,7 +305,7 @@ func (app *App) RunEditSubcmd(args []string) error {
 	fs.StringVar(&editor, "editor", os.Getenv("EDITOR"), "sets editor's name to use for editing Book's information")
 
 	setAttr := make(map[string]string)
-	fs.Var(util.NewKV(setAttr), "set", "set a new value for a book's attribute (format attribute=value)")
+	fs.Var(util.NewKV(setAttr), "set", "set a new value for a book's attribute (format attribute=value, like Identifier.asin=value for identifiers)")
 
 	defaultAttr := make(map[string]string)
 	fs.Var(util.NewKV(defaultAttr), "default", "set a new value for a book's attribute if the attribute is not yet set (format attribute=value)")
@@ -316,6 +391,7 @@ func (app *App) RunCheckSubcmd(args []string) error {
 
 	var failOnIssue bool
 	fs.BoolVar(&failOnIssue, "fail-on-issue", false, "exit with a non-zero exit status when a quality issue is found")
+	fs.Func("isbn-ranges", "loads ISBN ranges from the International ISBN Agency's RangeMessage XML file instead of the built-in ones", book.LoadISBNRanges)
 
 	var checkConformity bool
 	fs.BoolVar(&checkConformity, "conformity", false, "verify that book is a conform EPUB using w3.org epucheck tool")
@@ -323,6 +399,15 @@ func (app *App) RunCheckSubcmd(args []string) error {
 	var checkSecurity bool
 	fs.BoolVar(&checkSecurity, "security", false, "verify that book's content does not contain unsafe HTML")
 
+	var checkCover bool
+	fs.BoolVar(&checkCover, "cover", false, "verify that book has a cover of reasonable quality")
+	fs.IntVar(&book.CoverMinWidth, "cover-min-width", book.CoverMinWidth, "minimal width in pixels of a cover")
+	fs.IntVar(&book.CoverMinHeight, "cover-min-height", book.CoverMinHeight, "minimal height in pixels of a cover")
+	fs.Int64Var(&book.CoverMaxSize, "cover-max-size", book.CoverMaxSize, "maximal size in bytes of a cover")
+
+	var checkAccessibility bool
+	fs.BoolVar(&checkAccessibility, "accessibility", false, "verify that book's content is accessible to visually impaired readers")
+
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
 	}
@@ -364,6 +449,20 @@ func (app *App) RunCheckSubcmd(args []string) error {
 		}
 	}
 
+	if checkCover {
+		app.Verbose.Print("Check book's cover quality")
+		if err := b.CheckCover(); err != nil {
+			return fmt.Errorf("fail to check book's cover: %v", err)
+		}
+	}
+
+	if checkAccessibility {
+		app.Verbose.Print("Check book's content accessibility")
+		if err := b.CheckAccessibility(); err != nil {
+			return fmt.Errorf("fail to inspect book's accessibility: %v", err)
+		}
+	}
+
 	if err := app.Formatter.Execute(app.Stdout, b); err != nil {
 		return fmt.Errorf("fail to display book information: %v", err)
 	}
@@ -376,6 +475,413 @@ func (app *App) RunCheckSubcmd(args []string) error {
 	return nil
 }
 
+// RunCleanSubcmd executes the "clean" sub-command.
+func (app *App) RunCleanSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" clean", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] FILENAME\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	var output string
+	fs.StringVar(&output, "output", "", "file where to save the cleaned EPUB (default to FILENAME with a .clean.epub extension)")
+
+	var cleanSecurity bool
+	fs.BoolVar(&cleanSecurity, "security", false, "remove unsafe HTML and CSS from book's content")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+	path := fs.Arg(0)
+
+	if !cleanSecurity {
+		return fmt.Errorf("no cleaning operation requested\nRun %s -help", fs.Name())
+	}
+
+	if output == "" {
+		output = strings.TrimSuffix(path, filepath.Ext(path)) + ".clean.epub"
+	}
+
+	b := book.New()
+	b.Path = path
+
+	app.Verbose.Print("Remove unsafe content from book")
+	epub := new(bytes.Buffer)
+	report, err := b.SanitizeContent(epub)
+	if err != nil {
+		return fmt.Errorf("fail to clean '%s': %v", path, err)
+	}
+
+	if err := util.WriteFile(output, epub); err != nil {
+		return fmt.Errorf("fail to save cleaned book: %v", err)
+	}
+
+	for _, msg := range report {
+		fmt.Fprintln(app.Stdout, msg)
+	}
+	app.Verbose.Printf("Cleaned book saved to '%s' (%d removals)", output, len(report))
+
+	return nil
+}
+
+// RunCoverSubcmd executes the "cover" sub-command.
+func (app *App) RunCoverSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" cover", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] FILENAME\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	var output string
+	fs.StringVar(&output, "output", "", "file where to save the cover (default to standard output)")
+
+	var maxSize int
+	fs.IntVar(&maxSize, "resize", 0, "converts cover to JPEG and resizes it so that its width or height does not exceed the given size in pixels")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+	path := fs.Arg(0)
+
+	b, err := book.NewFromFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve information about '%s': %v", path, err)
+	}
+
+	app.Verbose.Print("Extract book's cover")
+	img, _, err := b.Cover()
+	if err != nil {
+		return fmt.Errorf("fail to extract cover from '%s': %v", path, err)
+	}
+
+	cover := new(bytes.Buffer)
+	if maxSize > 0 {
+		if err := util.ToJPEG(cover, img, maxSize); err != nil {
+			return fmt.Errorf("fail to resize cover: %v", err)
+		}
+	} else {
+		cover.Write(img)
+	}
+
+	if output == "" {
+		_, err := io.Copy(app.Stdout, cover)
+		return err
+	}
+
+	if err := util.WriteFile(output, cover); err != nil {
+		return fmt.Errorf("fail to save cover: %v", err)
+	}
+
+	return nil
+}
+
+// RunTextSubcmd executes the "text" sub-command.
+func (app *App) RunTextSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" text", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] FILENAME\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	var output string
+	fs.StringVar(&output, "output", "", "file where to save the text (default to standard output)")
+
+	var markdown bool
+	fs.BoolVar(&markdown, "markdown", false, "exports text as Markdown instead of plain text")
+
+	var statsOnly bool
+	fs.BoolVar(&statsOnly, "stats", false, "prints text's statistics (words, characters, reading time and pages) instead of the text")
+
+	fs.IntVar(&book.WordsPerPage, "words-per-page", book.WordsPerPage, "average number of words of a page used to estimate book's page count")
+	fs.IntVar(&book.WordsPerMinute, "words-per-minute", book.WordsPerMinute, "average number of words read per minute used to estimate book's reading time")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+	path := fs.Arg(0)
+
+	b, err := book.NewFromFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve information about '%s': %v", path, err)
+	}
+
+	app.Verbose.Print("Extract book's text")
+	txt := new(bytes.Buffer)
+	stats, err := b.WriteText(txt, markdown)
+	if err != nil {
+		return fmt.Errorf("fail to extract text from '%s': %v", path, err)
+	}
+
+	if statsOnly {
+		fmt.Fprintf(app.Stdout, "Words       : %d\n", stats.Words)
+		fmt.Fprintf(app.Stdout, "Characters  : %d\n", stats.Characters)
+		fmt.Fprintf(app.Stdout, "ReadingTime : %v\n", stats.ReadingTime)
+		fmt.Fprintf(app.Stdout, "Pages       : %d\n", stats.Pages)
+		return nil
+	}
+
+	if output == "" {
+		_, err := io.Copy(app.Stdout, txt)
+		return err
+	}
+
+	if err := util.WriteFile(output, txt); err != nil {
+		return fmt.Errorf("fail to save text: %v", err)
+	}
+
+	return nil
+}
+
+// RunAuthorsSubcmd executes the "authors" sub-command.
+func (app *App) RunAuthorsSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" authors", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...]\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&app.Library.Root, "root", app.Library.Root, "root folder where the books library is to be found")
+	fs.Func("authority", "loads authors' canonical names, aliases and pseudonyms from a JSON authority file", book.LoadAuthority)
+
+	var suggest bool
+	fs.BoolVar(&suggest, "suggest", false, "print suggested merges of authors' names variants in the authority file's format instead of the authors' list")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 0 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+
+	app.Verbose.Printf("List authors found in library '%s'", app.Library.Root)
+	count, sortnames := make(map[string]int), make(map[string]string)
+	if err := app.Library.Walk(func(b *book.Book) error {
+		for _, author := range b.Authors {
+			if _, exists := count[author]; !exists {
+				sortnames[author] = b.SortName(author)
+			}
+			count[author]++
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("fail to read library: %v", err)
+	}
+
+	authors := make([]string, 0, len(count))
+	for author := range count {
+		authors = append(authors, author)
+	}
+
+	// most frequent spelling is suggested as the canonical one.
+	sort.Slice(authors, func(i, j int) bool {
+		if count[authors[i]] != count[authors[j]] {
+			return count[authors[i]] > count[authors[j]]
+		}
+		return authors[i] < authors[j]
+	})
+	suggestions := book.SuggestAuthority(authors)
+
+	if suggest {
+		enc := json.NewEncoder(app.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(suggestions); err != nil {
+			return fmt.Errorf("fail to display suggested merges: %v", err)
+		}
+		return nil
+	}
+
+	sort.SliceStable(authors, func(i, j int) bool {
+		return sortnames[authors[i]] < sortnames[authors[j]]
+	})
+
+	for _, author := range authors {
+		fmt.Fprintf(app.Stdout, "%s (%d)\n", sortnames[author], count[author])
+	}
+
+	if len(suggestions) > 0 {
+		fmt.Fprintf(app.Stdout, "\nPossible variants of the same author:\n")
+		for _, a := range suggestions {
+			fmt.Fprintf(app.Stdout, "    %s: %s\n", a.Name, strings.Join(a.Aliases, ", "))
+		}
+	}
+
+	return nil
+}
+
+// RunGrepSubcmd executes the "grep" sub-command.
+func (app *App) RunGrepSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" grep", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] QUERY\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "QUERY is made of words or \"phrases\" combined using AND, OR, NOT (or -) and parentheses.\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&app.Library.Root, "root", app.Library.Root, "root folder where the books library is to be found")
+
+	var reindex bool
+	fs.BoolVar(&reindex, "reindex", false, "rebuilds the full-text index of the library before searching")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+
+	if reindex {
+		if err := app.Library.Reindex(); err != nil {
+			return fmt.Errorf("fail to index library: %v", err)
+		}
+	}
+
+	app.Verbose.Printf("Search '%s' in library '%s'", fs.Arg(0), app.Library.Root)
+	matches, err := app.Library.Grep(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("fail to search library: %v", err)
+	}
+
+	for _, m := range matches {
+		fmt.Fprintf(app.Stdout, "%s (%s): %s\n", m.Book, m.Chapter, m.Snippet)
+	}
+
+	return nil
+}
+
+// RunSeriesSubcmd executes the "series" sub-command.
+func (app *App) RunSeriesSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" series", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...]\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&app.Library.Root, "root", app.Library.Root, "root folder where the books library is to be found")
+	fs.Func("series-registry", "loads series' canonical names, aliases and length from a JSON file", book.LoadSeries)
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 0 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+
+	app.Verbose.Printf("List series found in library '%s'", app.Library.Root)
+	var books []*book.Book
+	if err := app.Library.Walk(func(b *book.Book) error {
+		books = append(books, b)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("fail to read library: %v", err)
+	}
+
+	for _, r := range book.NewSeriesReports(books) {
+		if r.Count > 0 {
+			fmt.Fprintf(app.Stdout, "%s (%d/%d)\n", r.Name, len(r.Owned), r.Count)
+		} else {
+			fmt.Fprintf(app.Stdout, "%s (%d)\n", r.Name, len(r.Owned))
+		}
+
+		printIndexes := func(label string, indexes []float64) {
+			if len(indexes) == 0 {
+				return
+			}
+
+			idx := make([]string, len(indexes))
+			for i, index := range indexes {
+				idx[i] = fmt.Sprint(index)
+			}
+			fmt.Fprintf(app.Stdout, "    %-16s: %s\n", label, strings.Join(idx, ", "))
+		}
+
+		printIndexes("owned", r.Owned)
+		printIndexes("missing", r.Missing)
+		printIndexes("duplicates", r.Duplicates)
+		printIndexes("outliers", r.Outliers)
+		if len(r.Spellings) > 0 {
+			fmt.Fprintf(app.Stdout, "    %-16s: %s\n", "other spellings", strings.Join(r.Spellings, ", "))
+		}
+	}
+
+	return nil
+}
+
+// RunCompareSubcmd executes the "compare" sub-command.
+func (app *App) RunCompareSubcmd(args []string) error {
+	fs := flag.NewFlagSet(myname+" compare", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [option...] BOOKinJSONFILE BOOKinJSONFILE\n", fs.Name())
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	fs.Func("comparator", "loads books' comparison thresholds and weights from a JSON file", book.LoadComparator)
+	fs.Func("authority", "loads authors' canonical names, aliases and pseudonyms from a JSON authority file", book.LoadAuthority)
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%v\nRun %s -help", err, fs.Name())
+	}
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("wrong number of arguments\nRun %s -help", fs.Name())
+	}
+
+	var books [2]*book.Book
+	for i, path := range fs.Args() {
+		//#nosec G304 -- path is explicitly supplied by end-user.
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("fail to read book's JSON: %v", err)
+		}
+
+		books[i] = book.New()
+		err = json.NewDecoder(f).Decode(&books[i])
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("fail to decode book's JSON from %s: %v", path, err)
+		}
+	}
+
+	app.Verbose.Printf("Compare '%s' with '%s'", fs.Arg(0), fs.Arg(1))
+	cmp := book.DefaultComparator.Compare(books[0], books[1])
+
+	enc := json.NewEncoder(app.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cmp); err != nil {
+		return fmt.Errorf("fail to display comparison: %v", err)
+	}
+
+	return nil
+}
+
 func main() {
 	app := NewApp()
 
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/pirmd/libro/util"
+
 	"github.com/pirmd/verify"
 )
 
@@ -196,6 +201,14 @@ func TestRunCheckSubcmd(t *testing.T) {
 		testRunCheckSubcmd("-security")(t)
 	})
 
+	t.Run("WithCoverCheck", func(t *testing.T) {
+		testRunCheckSubcmd("-cover")(t)
+	})
+
+	t.Run("WithAccessibilityCheck", func(t *testing.T) {
+		testRunCheckSubcmd("-accessibility")(t)
+	})
+
 	t.Run("WithExitIfIssue", func(t *testing.T) {
 		testRunCheckSubcmd("-fail-on-issue")(t)
 	})
@@ -250,6 +263,140 @@ func TestRunEditSubcmd(t *testing.T) {
 	t.Run("SetNew", func(t *testing.T) {
 		testRunEditSubcmd("-dont-edit", "-set", "Subject=libro&testing")(t)
 	})
+
+	t.Run("SetIdentifier", func(t *testing.T) {
+		testRunEditSubcmd("-dont-edit", "-set", "Identifier.asin=B00ABCDEFG")(t)
+	})
+}
+
+func TestRunAuthorsSubcmd(t *testing.T) {
+	testRunAuthorsSubcmd := func(args ...string) func(*testing.T) {
+		args = append([]string{"authors", "-root", testdataBooks}, args...)
+
+		return func(t *testing.T) {
+			testApp := newTestApp(t)
+
+			if err := testApp.Run(args); err != nil {
+				t.Fatalf("Fail to list authors of %s: %v", testdataBooks, err)
+			}
+
+			got := testApp.Stdout.(*bytes.Buffer).String()
+			if failure := verify.MatchGolden(t.Name(), got); failure != nil {
+				t.Fatalf("Output is not as expected.\n%v", failure)
+			}
+		}
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		testRunAuthorsSubcmd()(t)
+	})
+
+	t.Run("WithSuggest", func(t *testing.T) {
+		testRunAuthorsSubcmd("-suggest")(t)
+	})
+}
+
+func TestRunSeriesSubcmd(t *testing.T) {
+	testCases := []struct {
+		path        string
+		series      string
+		seriesIndex string
+	}{
+		{"pg2707.epub", "The History of Herodotus", "1"},
+		{"pg2456.epub", "The Histories of Herodotus", "2"},
+		{"pg54873.epub", "Voyages extraordinaires", "6"},
+		{"pg27573.epub", "Voyages imaginaires", "1"},
+		{"pg11.epub", "Alice", "1"},
+	}
+
+	testApp := newTestApp(t)
+
+	for _, tc := range testCases {
+		dst := filepath.Join(testApp.TestFolder.Root, tc.path)
+		if err := copyEpubWithSeries(dst, filepath.Join(testdataBooks, tc.path), tc.series, tc.seriesIndex); err != nil {
+			t.Fatalf("Fail to prepare %s: %v", tc.path, err)
+		}
+	}
+
+	if err := testApp.Run([]string{"series", "-root", testApp.TestFolder.Root}); err != nil {
+		t.Fatalf("Fail to list series of %s: %v", testApp.TestFolder.Root, err)
+	}
+
+	got := testApp.Stdout.(*bytes.Buffer).String()
+	if failure := verify.MatchGolden(t.Name(), got); failure != nil {
+		t.Fatalf("Output is not as expected.\n%v", failure)
+	}
+}
+
+func TestRunCompareSubcmd(t *testing.T) {
+	testCases := [][2]string{
+		{"alice.json", "alice_variant.json"},
+		{"alice.json", "verne.json"},
+	}
+
+	testApp := newTestApp(t)
+	for _, tc := range testCases {
+		args := []string{"compare", filepath.Join(testdata, "compare", tc[0]), filepath.Join(testdata, "compare", tc[1])}
+		if err := testApp.Run(args); err != nil {
+			t.Errorf("Fail to compare %s with %s: %v", tc[0], tc[1], err)
+		}
+	}
+
+	got := testApp.Stdout.(*bytes.Buffer).String()
+	if failure := verify.MatchGolden(t.Name(), got); failure != nil {
+		t.Fatalf("Output is not as expected.\n%v", failure)
+	}
+}
+
+func TestRunTextSubcmd(t *testing.T) {
+	testCases, err := filepath.Glob(filepath.Join(testdataBooks, "*.epub"))
+	if err != nil {
+		t.Fatalf("cannot read test data in %s: %v", testdataBooks, err)
+	}
+
+	testApp := newTestApp(t)
+	for _, tc := range testCases {
+		fmt.Fprintf(testApp.Stdout, "%s:\n", filepath.Base(tc))
+		if err := testApp.Run([]string{"text", "-stats", tc}); err != nil {
+			t.Errorf("Fail to compute text statistics of %s: %v", tc, err)
+		}
+	}
+
+	got := testApp.Stdout.(*bytes.Buffer).String()
+	if failure := verify.MatchGolden(t.Name(), got); failure != nil {
+		t.Fatalf("Output is not as expected.\n%v", failure)
+	}
+}
+
+func TestRunGrepSubcmd(t *testing.T) {
+	testApp := newTestApp(t)
+
+	// Herodotus' book is named so that it comes first in path order whereas
+	// matches are expected to be sorted by author.
+	for dst, src := range map[string]string{"pg11.epub": "pg11.epub", "herodotus.epub": "pg2707.epub"} {
+		if err := util.CopyFile(filepath.Join(testApp.TestFolder.Root, dst), filepath.Join(testdataBooks, src)); err != nil {
+			t.Fatalf("Fail to prepare library: %v", err)
+		}
+	}
+
+	testCases := [][]string{
+		{"grep", "-root", testApp.TestFolder.Root, `"down the rabbit hole"`},
+		{"grep", "-root", testApp.TestFolder.Root, `"Ionians of truly pure descent" OR ("white rabbit" -hatter)`},
+		{"remove", "-root", testApp.TestFolder.Root, "pg11.epub"},
+		{"grep", "-root", testApp.TestFolder.Root, `"down the rabbit hole" OR "Ionians of truly pure descent"`},
+	}
+
+	for _, tc := range testCases {
+		fmt.Fprintf(testApp.Stdout, "%s %s:\n", tc[0], tc[len(tc)-1])
+		if err := testApp.Run(tc); err != nil {
+			t.Errorf("Fail to run %v: %v", tc, err)
+		}
+	}
+
+	got := testApp.Stdout.(*bytes.Buffer).String()
+	if failure := verify.MatchGolden(t.Name(), got); failure != nil {
+		t.Fatalf("Output is not as expected.\n%v", failure)
+	}
 }
 
 func TestBookTemplates(t *testing.T) {
@@ -278,3 +425,55 @@ func TestBookTemplates(t *testing.T) {
 	})
 
 }
+
+// copyEpubWithSeries copies an EPUB, adding calibre's series metadata to its
+// package document.
+func copyEpubWithSeries(dst, src, series, seriesIndex string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	zw := zip.NewWriter(w)
+	for _, f := range r.File {
+		if !strings.HasSuffix(f.Name, ".opf") {
+			if err := zw.Copy(f); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		opf, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+
+		meta := fmt.Sprintf(`<meta name="calibre:series" content="%s"/><meta name="calibre:series_index" content="%s"/></metadata>`, series, seriesIndex)
+		opf = bytes.Replace(opf, []byte("</metadata>"), []byte(meta), 1)
+
+		fw, err := zw.Create(f.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(opf); err != nil {
+			return err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return w.Close()
+}
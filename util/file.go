@@ -17,13 +17,22 @@ func CopyFile(dst string, src string) error {
 	}
 	defer r.Close()
 
+	return WriteFile(dst, r)
+}
+
+// WriteFile writes the content of r to dst. Directories hosting dst are
+// created as needed.
+// if dst exists, write does not happen and an error is returned.
+// WriteFile forces write to disk (Sync() method of os.File), and value
+// certainty that write operation happens correctly over performance.
+func WriteFile(dst string, r io.Reader) error {
 	//#nosec G301 -- creation mode is before umask. Similar approach than os.Create.
 	if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
 		return err
 	}
 
 	//#nosec G302 -- creation mode is before umask. Similar approach than os.Create.
-	//#nosec G304 -- dst is cleaned before calling CopyFile (using libro.fullpath())
+	//#nosec G304 -- dst is cleaned before calling WriteFile (using libro.fullpath())
 	w, err := os.OpenFile(filepath.Clean(dst), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
 	if err != nil {
 		return err
@@ -36,11 +36,13 @@ var (
 	//  - lower:  convert string to lower-case
 	//  - title:  convert string to title-case
 	//  - upper:  convert string to upper-case
+	//  - wrap :  wrap text (usually Markdown) to the given width
 	StringsFuncMap = template.FuncMap{
 		"join":  strings.Join,
 		"lower": strings.ToLower,
 		"title": strings.ToTitle,
 		"upper": strings.ToUpper,
+		"wrap":  func(width int, text string) string { return WrapText(text, width) },
 	}
 
 	// SerializationFuncMap provides standard functions to serialize/deserialize an
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+
+	// Register supported image formats.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// jpegQuality is the quality used to encode JPEG images.
+const jpegQuality = 85
+
+// ToJPEG converts an image (in any format supported by image package) into
+// JPEG. If maxSize is not zero, image is resized so that its biggest
+// dimension does not exceed maxSize.
+func ToJPEG(w io.Writer, img []byte, maxSize int) error {
+	src, format, err := image.Decode(bytes.NewReader(img))
+	if err != nil {
+		return err
+	}
+
+	if maxSize == 0 {
+		if format == "jpeg" {
+			_, err := w.Write(img)
+			return err
+		}
+
+		return jpeg.Encode(w, src, &jpeg.Options{Quality: jpegQuality})
+	}
+
+	return jpeg.Encode(w, Resize(src, maxSize), &jpeg.Options{Quality: jpegQuality})
+}
+
+// Resize scales down an image so that its biggest dimension does not exceed
+// maxSize, keeping its aspect ratio. Images that are already small enough are
+// returned as-is.
+// Resize uses a simple box filter that averages source pixels covered by
+// each destination pixel.
+func Resize(src image.Image, maxSize int) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw <= maxSize && sh <= maxSize {
+		return src
+	}
+
+	dw, dh := maxSize, sh*maxSize/sw
+	if sh > sw {
+		dw, dh = sw*maxSize/sh, maxSize
+	}
+	if dw == 0 {
+		dw = 1
+	}
+	if dh == 0 {
+		dh = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := b.Min.Y+y*sh/dh, b.Min.Y+(y+1)*sh/dh
+		for x := 0; x < dw; x++ {
+			x0, x1 := b.Min.X+x*sw/dw, b.Min.X+(x+1)*sw/dw
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			if n > 0 {
+				dst.Set(x, y, color.RGBA64{
+					R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n),
+				})
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"image"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	testCases := []struct {
+		w, h         int
+		maxSize      int
+		wantW, wantH int
+	}{
+		{600, 900, 200, 133, 200},
+		{900, 600, 200, 200, 133},
+		{100, 150, 200, 100, 150},
+	}
+
+	for _, tc := range testCases {
+		img := image.NewRGBA(image.Rect(0, 0, tc.w, tc.h))
+
+		got := Resize(img, tc.maxSize).Bounds()
+		if got.Dx() != tc.wantW || got.Dy() != tc.wantH {
+			t.Errorf("Resize %dx%d to %d failed.\nWant: %dx%d\nGot : %dx%d", tc.w, tc.h, tc.maxSize, tc.wantW, tc.wantH, got.Dx(), got.Dy())
+		}
+	}
+}
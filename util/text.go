@@ -0,0 +1,73 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// reLinePrefix is a regexp that captures the Markdown prefix of a line:
+// indentation and block quote markers followed by an optional list marker.
+var reLinePrefix = regexp.MustCompile(`^([ \t]*(?:>[ \t]?)*)((?:[-*+]|\d+[.)])[ \t]+)?`)
+
+// WrapText wraps a text, usually written in Markdown, so that its lines do not
+// exceed the given width (in characters) whenever possible. Wrapped lines keep
+// their Markdown context: block quote markers are repeated and the content of
+// list items is indented. Headings, tables and code blocks are left as-is, as
+// well as words that are longer than width.
+// A width lower or equal to 0 leaves text unchanged.
+func WrapText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	var wrapped []string
+	var inCode bool
+
+	for _, line := range strings.Split(text, "\n") {
+		m := reLinePrefix.FindStringSubmatch(line)
+		content := line[len(m[0]):]
+
+		if strings.HasPrefix(content, "```") {
+			inCode = !inCode
+		}
+
+		if inCode || utf8.RuneCountInString(line) <= width ||
+			strings.HasPrefix(content, "```") || strings.HasPrefix(content, "#") || strings.HasPrefix(content, "|") {
+			wrapped = append(wrapped, line)
+			continue
+		}
+
+		wrapped = append(wrapped, wrapLine(content, m[0], m[1]+strings.Repeat(" ", len(m[2])), width)...)
+	}
+
+	return strings.Join(wrapped, "\n")
+}
+
+// wrapLine splits a line's content into lines of at most width characters.
+// The first line starts with prefix, the following ones with indent. Markdown
+// hard line break (two trailing spaces) is kept on the last line.
+func wrapLine(content, prefix, indent string, width int) []string {
+	var lines []string
+
+	cur, curLen := prefix, utf8.RuneCountInString(prefix)
+	empty := true
+	for _, word := range strings.Fields(content) {
+		wordLen := utf8.RuneCountInString(word)
+
+		if !empty && curLen+1+wordLen > width {
+			lines = append(lines, cur)
+			cur, curLen, empty = indent, utf8.RuneCountInString(indent), true
+		}
+
+		if !empty {
+			cur, curLen = cur+" ", curLen+1
+		}
+		cur, curLen, empty = cur+word, curLen+wordLen, false
+	}
+
+	if strings.HasSuffix(content, "  ") {
+		cur += "  "
+	}
+	return append(lines, cur)
+}
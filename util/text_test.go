@@ -0,0 +1,65 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	testCases := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{
+			in:    "Hello Gophers, golang is so nice!",
+			width: 15,
+			want:  "Hello Gophers,\ngolang is so\nnice!",
+		},
+		{
+			in:    "Hello Gophers!\n\nGolang is so nice!",
+			width: 10,
+			want:  "Hello\nGophers!\n\nGolang is\nso nice!",
+		},
+		{
+			in:    "- first item of the list\n  1. nested item of the list",
+			width: 15,
+			want:  "- first item of\n  the list\n  1. nested\n     item of\n     the list",
+		},
+		{
+			in:    "> To be or not to be, that is the question",
+			width: 20,
+			want:  "> To be or not to\n> be, that is the\n> question",
+		},
+		{
+			in:    "Hello golang  \nGophers",
+			width: 8,
+			want:  "Hello\ngolang  \nGophers",
+		},
+		{
+			in:    "# A long title that is not wrapped\n| a table | that is not wrapped |",
+			width: 10,
+			want:  "# A long title that is not wrapped\n| a table | that is not wrapped |",
+		},
+		{
+			in:    "```\nfmt.Println(\"a long line of code\")\n```",
+			width: 10,
+			want:  "```\nfmt.Println(\"a long line of code\")\n```",
+		},
+		{
+			in:    "Antidisestablishmentarianism is long",
+			width: 10,
+			want:  "Antidisestablishmentarianism\nis long",
+		},
+		{
+			in:    "Not wrapped",
+			width: 0,
+			want:  "Not wrapped",
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := WrapText(tc.in, tc.width); got != tc.want {
+			t.Errorf("Wrapping %q to %d failed.\nWant: %q\nGot : %q", tc.in, tc.width, tc.want, got)
+		}
+	}
+}